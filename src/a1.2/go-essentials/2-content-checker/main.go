@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"math"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/devwithpeet/tutorials/src/a1.2/go-essentials/2-content-checker/pkg"
 )
@@ -15,10 +21,37 @@ type Command string
 const Version = "0.1.8"
 
 const (
-	PrintCommand   Command = "print"
-	ErrorsCommand  Command = "errors"
-	StatsCommand   Command = "stats"
-	VersionCommand Command = "version"
+	PrintCommand       Command = "print"
+	ErrorsCommand      Command = "errors"
+	StatsCommand       Command = "stats"
+	VersionCommand     Command = "version"
+	ReorderCommand     Command = "reorder"
+	SyncCommand        Command = "sync-episodes"
+	TagsCommand        Command = "tags"
+	CoverageCommand    Command = "coverage"
+	GraphCommand       Command = "graph"
+	SpellCommand       Command = "spell"
+	ReadabilityCommand Command = "readability"
+	ExportCommand      Command = "export"
+	LSPCommand         Command = "lsp"
+	ServeCommand       Command = "serve"
+	StaleCommand       Command = "stale"
+	ExplainCommand     Command = "explain"
+	NextCommand        Command = "next"
+	DiffCommand        Command = "diff"
+	ChangelogCommand   Command = "changelog"
+)
+
+type Format string
+
+const (
+	TerminalFormat Format = "terminal"
+	CSVFormat      Format = "csv"
+	JSONFormat     Format = "json"
+	DotFormat      Format = "dot"
+	MermaidFormat  Format = "mermaid"
+	MarkdownFormat Format = "markdown"
+	AtomFormat     Format = "atom"
 )
 
 func main() {
@@ -27,26 +60,69 @@ func main() {
 		action = Command(os.Args[1])
 	}
 
-	root := "."
-	if len(os.Args) > 2 {
-		root = os.Args[2]
+	// the site root is optional, the flags of the command follow it
+	root, args := splitRoot(os.Args[min(len(os.Args), 2):])
+
+	switch action {
+	case VersionCommand:
+		fmt.Println("Version:", Version)
+
+		return
+
+	case ReorderCommand:
+		Reorder(chapterArgs(ReorderCommand))
+
+		return
+
+	case SyncCommand:
+		SyncEpisodes(chapterArgs(SyncCommand))
+
+		return
+
+	case SpellCommand:
+		Spell(root, args)
+
+		return
+
+	case LSPCommand:
+		LanguageServer(root)
+
+		return
+
+	case ServeCommand:
+		Serve(root, args)
+
+		return
+
+	case ExplainCommand:
+		Explain(splitRoot(os.Args[2:]))
+
+		return
+
+	case DiffCommand:
+		Diff(os.Args[2:])
+
+		return
+
+	case ErrorsCommand:
+		// explicit documents are checked one by one instead of crawling the whole site
+		if documentsRoot, args := splitRoot(os.Args[2:]); len(args) > 0 {
+			DocumentErrors(documentsRoot, args)
+
+			return
+		}
 	}
 
-	// collect markdown files
-	files, err := findFiles(root)
+	result, err := pkg.NewChecker(pkg.WithRoot(root), pkg.WithMaxErrors(maxErrors)).Check()
 	if err != nil {
-		panic("cannot find files in root: " + root + ", error: " + err.Error())
+		panic(err.Error())
 	}
 
-	// fetch markdown files
-	courses, count := CrawlMarkdownFiles(files, maxErrors)
+	printSkipped(result)
 
-	Prepare(courses)
+	courses, count := result.Courses, result.Count
 
 	switch action {
-	case VersionCommand:
-		fmt.Println("Version:", Version)
-
 	case PrintCommand:
 		statesAllowed := map[pkg.State]struct{}{
 			pkg.Complete:   {},
@@ -57,25 +133,23 @@ func main() {
 		printIndex := false
 		printNonIndex := true
 
-		if len(os.Args) > 3 {
-			for _, arg := range os.Args[3:] {
-				switch arg {
-				case "--without-non-index":
-					printNonIndex = false
-				case "--with-index":
-					printIndex = true
-				case "complete":
-					statesAllowed = map[pkg.State]struct{}{
-						pkg.Complete: {},
-					}
-				case "incomplete":
-					statesAllowed = map[pkg.State]struct{}{
-						pkg.Incomplete: {},
-					}
-				case "stub":
-					statesAllowed = map[pkg.State]struct{}{
-						pkg.Stub: {},
-					}
+		for _, arg := range args {
+			switch arg {
+			case "--without-non-index":
+				printNonIndex = false
+			case "--with-index":
+				printIndex = true
+			case "complete":
+				statesAllowed = map[pkg.State]struct{}{
+					pkg.Complete: {},
+				}
+			case "incomplete":
+				statesAllowed = map[pkg.State]struct{}{
+					pkg.Incomplete: {},
+				}
+			case "stub":
+				statesAllowed = map[pkg.State]struct{}{
+					pkg.Stub: {},
 				}
 			}
 		}
@@ -83,74 +157,208 @@ func main() {
 		Print(count, courses, statesAllowed, printIndex, printNonIndex)
 
 	case ErrorsCommand:
-		Errors(count, courses)
+		Errors(result)
 
 	case StatsCommand:
 		courses.Stats()
 
+	case TagsCommand:
+		fmt.Print(courses.TagStats().String(result.Vocabulary))
+
+	case CoverageCommand:
+		Coverage(courses, args)
+
+	case GraphCommand:
+		Graph(courses, args)
+
+	case ReadabilityCommand:
+		fmt.Print(courses.ReadabilityStats().String())
+
+	case ExportCommand:
+		Export(courses, args)
+
+	case StaleCommand:
+		Stale(root, result, args)
+
+	case NextCommand:
+		Next(courses, args)
+
+	case ChangelogCommand:
+		Changelog(root, courses, args)
+
 	default:
 		panic("unknown command: " + string(action))
 	}
 }
 
-func findFiles(root string) ([]string, error) {
-	pattern := filepath.Join(root, "content") + "/**/**/*.md"
+const (
+	contentDir = "content"
+	maxErrors  = 10
+)
+
+// printSkipped prints the files which are not checked and the checks which are not run
+func printSkipped(result pkg.Result) {
+	for _, filePath := range result.Skipped {
+		fmt.Println("Skipping:", filePath)
+	}
 
-	return filepath.Glob(pattern)
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
 }
 
-const maxErrors = 10
+func LanguageServer(root string) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		panic("cannot find root: " + err.Error())
+	}
 
-func CrawlMarkdownFiles(matches []string, maxErrors int) (pkg.Courses, int) {
-	if maxErrors < 0 {
-		maxErrors = math.MaxInt
+	// the git history is read once, reading it on every change of a document would slow down the diagnostics
+	commitTimes, err := pkg.CommitTimes(root)
+	if err != nil {
+		commitTimes = map[string]time.Time{}
 	}
 
-	result := make(pkg.Courses, 0, len(matches))
+	analyze := func(documents map[string]string) (pkg.Courses, error) {
+		result, err := pkg.NewChecker(pkg.WithRoot(root), pkg.WithCommitTimes(commitTimes), pkg.WithDocuments(documents)).Check()
 
-	var count, errCount int
+		return result.Courses, err
+	}
 
-	for _, filePath := range matches {
-		if errCount >= maxErrors {
-			break
-		}
+	vocabulary, err := pkg.NewChecker(pkg.WithRoot(root)).TagVocabulary()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	server := pkg.NewLanguageServer(os.Stdin, os.Stdout, analyze, vocabulary.Tags())
+	if err := server.Serve(); err != nil {
+		panic("language server failed: " + err.Error())
+	}
+}
+
+func Serve(root string, args []string) {
+	flags := flag.NewFlagSet(string(ServeCommand), flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	interval := flags.Duration("interval", 0, "interval of checking the site for changes, 0 disables polling")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	checker := pkg.NewChecker(pkg.WithRoot(root))
+
+	crawl := func() (pkg.Courses, error) {
+		result, err := checker.Check()
+
+		return result.Courses, err
+	}
+
+	scan := func() (map[string]time.Time, error) {
+		return scanSite(root, checker)
+	}
+
+	server := pkg.NewAPIServer(crawl, scan)
+	if _, err := server.Refresh(); err != nil {
+		panic("cannot load site: " + err.Error())
+	}
+
+	if *interval > 0 {
+		go server.Poll(context.Background(), *interval, func(err error) {
+			fmt.Fprintln(os.Stderr, "cannot refresh site:", err)
+		})
+	}
 
-		parts := strings.Split(filePath, "/")
+	fmt.Println("Listening on", *addr)
 
-		if len(parts) < 3 {
-			fmt.Println("Skipping:", filePath)
+	if err := http.ListenAndServe(*addr, server.Handler()); err != nil {
+		panic("server failed: " + err.Error())
+	}
+}
+
+// scanSite returns the modification times of the markdown files and the files configuring the checks
+func scanSite(root string, checker *pkg.Checker) (map[string]time.Time, error) {
+	files, err := checker.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	files = append(files, filepath.Join(root, pkg.TagVocabularyFileName), filepath.Join(root, pkg.ConfigFileName))
+
+	modTimes := make(map[string]time.Time, len(files))
+
+	for _, filePath := range files {
+		info, err := os.Stat(filePath)
+		if errors.Is(err, os.ErrNotExist) {
 			continue
+		} else if err != nil {
+			return nil, err
 		}
 
-		course := parts[len(parts)-3]
-		chapter := parts[len(parts)-2]
-		page := parts[len(parts)-1]
+		modTimes[filePath] = info.ModTime()
+	}
 
-		rawContent, err := os.ReadFile(filePath)
-		if err != nil {
-			panic("cannot open file: " + filePath)
+	return modTimes, nil
+}
+
+func Spell(root string, args []string) {
+	flags := flag.NewFlagSet(string(SpellCommand), flag.ExitOnError)
+	dictionaryPath := flags.String("dictionary", filepath.Join(root, pkg.ProjectDictionaryFileName), "project dictionary file, one word per line")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	english, err := pkg.EnglishDictionary()
+	if err != nil {
+		panic("cannot load english dictionary: " + err.Error())
+	}
+
+	dictionaries := []pkg.Dictionary{english}
+
+	rawDictionary, err := os.ReadFile(*dictionaryPath)
+	if err == nil {
+		dictionaries = append(dictionaries, pkg.ParseDictionary(string(rawDictionary)))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		panic("cannot open file: " + *dictionaryPath)
+	}
+
+	files, err := pkg.ReadMarkdownFiles(filepath.Join(root, contentDir))
+	if err != nil {
+		panic("cannot read markdown files in: " + root + ", error: " + err.Error())
+	}
+
+	filePaths := make([]string, 0, len(files))
+	for filePath := range files {
+		// the bundled dictionary is english, translations are not checked
+		if pkg.Language(filePath) != "" {
+			continue
 		}
 
-		content, err := pkg.ParseMarkdown(string(rawContent))
+		filePaths = append(filePaths, filePath)
+	}
+
+	sort.Strings(filePaths)
+
+	fmt.Println("Processed", len(filePaths), "markdown files")
+
+	checker := pkg.NewSpellChecker(dictionaries...)
+	errorsFound := false
+
+	for _, filePath := range filePaths {
+		issues, err := checker.Check(files[filePath])
 		if err != nil {
 			panic("cannot parse markdown: " + filePath + ", err: " + err.Error())
 		}
 
-		result = result.Add(filePath, course, chapter, page, content)
+		for _, issue := range issues {
+			errorsFound = true
 
-		if len(content.GetIssues(filePath)) > 0 {
-			errCount++
+			fmt.Println(filePath + " - " + issue.String())
 		}
-
-		count++
 	}
 
-	return result, count
-}
-
-func Prepare(courses pkg.Courses) {
-	for _, course := range courses {
-		course.Prepare()
+	if errorsFound {
+		os.Exit(1)
 	}
 }
 
@@ -162,23 +370,438 @@ func Print(count int, courses pkg.Courses, statesAllowed map[pkg.State]struct{},
 	}
 }
 
-func Errors(count int, courses pkg.Courses) {
-	fmt.Println("Processed", count, "markdown files")
+func Errors(result pkg.Result) {
+	fmt.Println("Processed", result.Count, "markdown files")
 
-	errorsFound := false
+	errors := result.Errors()
+	if len(errors) == 0 {
+		return
+	}
 
-	for _, course := range courses {
-		errors := course.GetErrors()
-		if len(errors) == 0 {
-			continue
+	fmt.Println(strings.Join(errors, "\n"))
+
+	os.Exit(1)
+}
+
+// chapterArgs returns the chapter directory and the flags of a command working on a chapter, the usage is printed if
+// the chapter directory is missing
+func chapterArgs(command Command) (string, []string) {
+	if len(os.Args) < 3 || strings.HasPrefix(os.Args[2], "-") {
+		fmt.Fprintf(os.Stderr, "usage: %s %s <chapter-dir> [flags]\n", filepath.Base(os.Args[0]), command)
+		os.Exit(2)
+	}
+
+	return os.Args[2], os.Args[3:]
+}
+
+// splitRoot returns the root given before the flags and the documents of a command, the root defaults to the current
+// directory
+func splitRoot(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || filepath.Ext(args[0]) == ".md" {
+		return ".", args
+	}
+
+	return args[0], args[1:]
+}
+
+// DocumentErrors checks the given documents only, the checks needing whole chapters or courses are skipped. With
+// --stdin a single document is read from the standard input, its path is given by --path.
+func DocumentErrors(root string, args []string) {
+	flags := flag.NewFlagSet(string(ErrorsCommand), flag.ExitOnError)
+	stdin := flags.Bool("stdin", false, "read the document from the standard input")
+	stdinPath := flags.String("path", "", "path of the document read from the standard input")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	options := []pkg.Option{pkg.WithRoot(root)}
+	filePaths := flags.Args()
+
+	if *stdin {
+		if *stdinPath == "" {
+			panic("cannot read standard input: --path is missing")
 		}
 
-		errorsFound = true
+		rawContent, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			panic("cannot read standard input: " + err.Error())
+		}
 
-		fmt.Println(strings.Join(errors, "\n"))
+		options = append(options, pkg.WithDocuments(map[string]string{*stdinPath: string(rawContent)}))
+		filePaths = append(filePaths, *stdinPath)
 	}
 
-	if errorsFound {
-		os.Exit(1)
+	result, err := pkg.NewChecker(options...).CheckDocuments(filePaths)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	printSkipped(result)
+
+	Errors(result)
+}
+
+// Explain prints why the state of the given page is calculated the way it is and what is missing for a complete page
+func Explain(root string, args []string) {
+	flags := flag.NewFlagSet(string(ExplainCommand), flag.ExitOnError)
+	format := flags.String("format", string(TerminalFormat), "output format: terminal or json")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	if flags.NArg() != 1 {
+		panic("cannot explain state: exactly one file is expected")
+	}
+
+	explanation, err := pkg.NewChecker(pkg.WithRoot(root)).ExplainState(flags.Arg(0))
+	if err != nil {
+		panic("cannot explain state: " + err.Error())
+	}
+
+	var output string
+
+	switch Format(*format) {
+	case TerminalFormat:
+		output = explanation.String()
+	case JSONFormat:
+		output, err = explanation.JSON()
+	default:
+		panic("unknown format: " + *format)
+	}
+
+	if err != nil {
+		panic("cannot render explanation: " + err.Error())
+	}
+
+	fmt.Print(output)
+}
+
+func Coverage(courses pkg.Courses, args []string) {
+	flags := flag.NewFlagSet(string(CoverageCommand), flag.ExitOnError)
+	format := flags.String("format", string(TerminalFormat), "output format: terminal, csv or json")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	coverage := courses.Coverage()
+
+	var (
+		output string
+		err    error
+	)
+
+	switch Format(*format) {
+	case TerminalFormat:
+		output = coverage.String()
+	case CSVFormat:
+		output, err = coverage.CSV()
+	case JSONFormat:
+		output, err = coverage.JSON()
+	default:
+		panic("unknown format: " + *format)
+	}
+
+	if err != nil {
+		panic("cannot render coverage: " + err.Error())
+	}
+
+	fmt.Print(output)
+}
+
+func Export(courses pkg.Courses, args []string) {
+	flags := flag.NewFlagSet(string(ExportCommand), flag.ExitOnError)
+	format := flags.String("format", string(JSONFormat), "output format: json or csv")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	catalog := courses.Export()
+
+	var (
+		output string
+		err    error
+	)
+
+	switch Format(*format) {
+	case JSONFormat:
+		output, err = catalog.JSON()
+	case CSVFormat:
+		output, err = catalog.CSV()
+	default:
+		panic("unknown format: " + *format)
+	}
+
+	if err != nil {
+		panic("cannot render catalog: " + err.Error())
+	}
+
+	fmt.Print(output)
+}
+
+func Stale(root string, result pkg.Result, args []string) {
+	flags := flag.NewFlagSet(string(StaleCommand), flag.ExitOnError)
+	format := flags.String("format", string(TerminalFormat), "output format: terminal or json")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	// pages without a last reviewed date are considered reviewed when they were last committed
+	commitTimes, gitErr := pkg.CommitTimes(root)
+	if gitErr != nil {
+		fmt.Fprintln(os.Stderr, "commit dates are not used:", gitErr)
+	}
+
+	stalePages := result.Courses.StalePages(result.Config, commitTimes, time.Now())
+
+	var (
+		output string
+		err    error
+	)
+
+	switch Format(*format) {
+	case TerminalFormat:
+		output = stalePages.String()
+	case JSONFormat:
+		output, err = stalePages.JSON()
+	default:
+		panic("unknown format: " + *format)
+	}
+
+	if err != nil {
+		panic("cannot render stale pages: " + err.Error())
+	}
+
+	fmt.Print(output)
+}
+
+// Diff compares two versions of the site, each given either as a directory or as a git revision of the site in --root
+func Diff(args []string) {
+	flags := flag.NewFlagSet(string(DiffCommand), flag.ExitOnError)
+	format := flags.String("format", string(TerminalFormat), "output format: terminal or markdown")
+	root := flags.String("root", ".", "directory of the site the git revisions are read from")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	if flags.NArg() != 2 {
+		panic("cannot diff: two directories or git revisions are expected")
+	}
+
+	before, beforeRoot := diffSide(*root, flags.Arg(0))
+	after, afterRoot := diffSide(*root, flags.Arg(1))
+
+	diff := pkg.DiffCourses(before, beforeRoot, after, afterRoot)
+
+	switch Format(*format) {
+	case TerminalFormat:
+		fmt.Print(diff.String())
+	case MarkdownFormat:
+		fmt.Print(diff.Markdown())
+	default:
+		panic("unknown format: " + *format)
+	}
+}
+
+// diffSide checks a version of the site, returning its courses and the root its file paths are joined with
+func diffSide(root, version string) (pkg.Courses, string) {
+	options := []pkg.Option{pkg.WithRoot(version)}
+
+	if info, err := os.Stat(version); err != nil || !info.IsDir() {
+		fsys, err := pkg.GitFS(root, version)
+		if err != nil {
+			panic(err.Error())
+		}
+
+		options = []pkg.Option{pkg.WithRoot(root), pkg.WithFS(fsys)}
+		version = root
+	}
+
+	result, err := pkg.NewChecker(options...).Check()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return result.Courses, version
+}
+
+// Changelog lists the pages which became complete since the given date or git revision, the output is written to a file
+// if --output is given
+func Changelog(root string, courses pkg.Courses, args []string) {
+	flags := flag.NewFlagSet(string(ChangelogCommand), flag.ExitOnError)
+	since := flags.String("since", "", "date (YYYY-MM-DD) or git revision to list the completed pages since")
+	format := flags.String("format", string(MarkdownFormat), "output format: markdown or atom")
+	title := flags.String("title", "Newly completed lessons", "title of the changelog")
+	baseURL := flags.String("base-url", "https://devwithpeet.com", "URL of the site the links of the pages start with")
+	author := flags.String("author", "devwithpeet", "author of the atom feed")
+	outputPath := flags.String("output", "", "file to write the changelog to, the standard output is used if empty")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	if *since == "" {
+		panic("cannot create changelog: --since is missing")
+	}
+
+	completionTimes, err := pkg.CompletionTimes(root, *since)
+	if err != nil {
+		panic("cannot create changelog: " + err.Error())
+	}
+
+	changelog := courses.Changelog(completionTimes, *baseURL)
+
+	var output string
+
+	switch Format(*format) {
+	case MarkdownFormat:
+		output = changelog.Markdown(*title)
+	case AtomFormat:
+		output, err = changelog.Atom(*title, *baseURL, *author)
+	default:
+		panic("unknown format: " + *format)
+	}
+
+	if err != nil {
+		panic("cannot render changelog: " + err.Error())
+	}
+
+	if *outputPath == "" {
+		fmt.Print(output)
+
+		return
+	}
+
+	if err := os.WriteFile(*outputPath, []byte(output), 0o644); err != nil {
+		panic("cannot write file: " + *outputPath + ", err: " + err.Error())
+	}
+}
+
+func Next(courses pkg.Courses, args []string) {
+	flags := flag.NewFlagSet(string(NextCommand), flag.ExitOnError)
+	format := flags.String("format", string(TerminalFormat), "output format: terminal or json")
+	course := flags.String("course", "", "only rank the pages of the given course")
+	limit := flags.Int("limit", 10, "number of pages to print, 0 prints every page which is not complete")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	nextPages := courses.NextPages(*course, *limit)
+
+	var (
+		output string
+		err    error
+	)
+
+	switch Format(*format) {
+	case TerminalFormat:
+		output = nextPages.String()
+	case JSONFormat:
+		output, err = nextPages.JSON()
+	default:
+		panic("unknown format: " + *format)
+	}
+
+	if err != nil {
+		panic("cannot render next pages: " + err.Error())
+	}
+
+	fmt.Print(output)
+}
+
+func Graph(courses pkg.Courses, args []string) {
+	flags := flag.NewFlagSet(string(GraphCommand), flag.ExitOnError)
+	format := flags.String("format", string(DotFormat), "output format: dot or mermaid")
+	course := flags.String("course", "", "only include the pages of the given course and their prerequisites")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	graph := courses.PrerequisiteGraph(*course)
+
+	switch Format(*format) {
+	case DotFormat:
+		fmt.Print(graph.Dot())
+	case MermaidFormat:
+		fmt.Print(graph.Mermaid())
+	default:
+		panic("unknown format: " + *format)
+	}
+}
+
+func Reorder(chapterDir string, args []string) {
+	flags := flag.NewFlagSet(string(ReorderCommand), flag.ExitOnError)
+	move := flags.String("move", "", "slug of the page to move")
+	after := flags.String("after", "", "slug of the page to place the moved page after, empty means first")
+	renumber := flags.Bool("renumber", false, "renumber every page of the chapter")
+	step := flags.Int("step", 10, "difference between the weights of neighbouring pages")
+	dryRun := flags.Bool("dry-run", false, "only print the changes without applying them")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	// links to the reordered pages might be found anywhere in the content directory
+	contentDir := filepath.Dir(filepath.Dir(filepath.Clean(chapterDir)))
+
+	files, err := pkg.ReadMarkdownFiles(contentDir)
+	if err != nil {
+		panic("cannot read markdown files in: " + contentDir + ", error: " + err.Error())
+	}
+
+	plan, err := pkg.PlanReorder(files, chapterDir, pkg.ReorderOperation{
+		Move:     *move,
+		After:    *after,
+		Renumber: *renumber,
+		Step:     *step,
+	})
+	if err != nil {
+		panic("cannot reorder chapter: " + chapterDir + ", error: " + err.Error())
+	}
+
+	fmt.Print(plan.Diff())
+
+	if *dryRun {
+		return
+	}
+
+	if err := plan.Apply(); err != nil {
+		panic("cannot apply changes: " + err.Error())
+	}
+}
+
+func SyncEpisodes(chapterDir string, args []string) {
+	flags := flag.NewFlagSet(string(SyncCommand), flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only print the changes without applying them")
+
+	if err := flags.Parse(args); err != nil {
+		panic("cannot parse arguments: " + err.Error())
+	}
+
+	files, err := pkg.ReadMarkdownFiles(chapterDir)
+	if err != nil {
+		panic("cannot read markdown files in: " + chapterDir + ", error: " + err.Error())
+	}
+
+	changes, err := pkg.PlanEpisodesSync(files, chapterDir)
+	if err != nil {
+		panic("cannot sync episodes of chapter: " + chapterDir + ", error: " + err.Error())
+	}
+
+	fmt.Print(changes.Diff())
+
+	if *dryRun {
+		return
+	}
+
+	if err := changes.Apply(); err != nil {
+		panic("cannot apply changes: " + err.Error())
 	}
 }
@@ -0,0 +1,794 @@
+package pkg
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type NodeKind string
+
+const (
+	// block nodes
+	HeadingNode       NodeKind = "heading"
+	ParagraphNode     NodeKind = "paragraph"
+	ListNode          NodeKind = "list"
+	ListItemNode      NodeKind = "list item"
+	CodeBlockNode     NodeKind = "code block"
+	CommentNode       NodeKind = "comment"
+	ThematicBreakNode NodeKind = "thematic break"
+
+	// inline nodes
+	LinkNode      NodeKind = "link"
+	ImageNode     NodeKind = "image"
+	ShortcodeNode NodeKind = "shortcode"
+)
+
+// Position is a location in a markdown file, lines and columns start at 1, columns are counted in bytes
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) Before(other Position) bool {
+	if p.Line != other.Line {
+		return p.Line < other.Line
+	}
+
+	return p.Column < other.Column
+}
+
+// Node is an element of a markdown document. Headings and paragraphs contain their inline nodes (links, images and
+// shortcodes), lists contain their items and list items contain their own blocks.
+type Node struct {
+	Kind  NodeKind
+	Start Position
+	// EndLine is the last line of the node
+	EndLine int
+	// Level is the level of headings
+	Level int
+	// Text is the title of headings, the text of paragraphs, list items and links, the content of code blocks and
+	// comments, the alt text of images and the source of shortcodes
+	Text string
+	// Language is the first word of the info string of fenced code blocks
+	Language string
+	Fenced   bool
+	// Unclosed is true for fenced code blocks and comments reaching the end of their container
+	Unclosed bool
+	// Destination is the target of links and images
+	Destination string
+	// Name and Args are the name and the arguments of shortcodes, quotes are removed from the arguments
+	Name     string
+	Args     []string
+	Children []*Node
+}
+
+// Inlines returns the inline nodes of headings and paragraphs and the ones of the first paragraph of list items
+func (n *Node) Inlines() []*Node {
+	switch n.Kind {
+	case HeadingNode, ParagraphNode:
+		return n.Children
+	case ListItemNode:
+		if len(n.Children) > 0 && n.Children[0].Kind == ParagraphNode {
+			return n.Children[0].Children
+		}
+	}
+
+	return nil
+}
+
+// walkNodes calls fn for the nodes and their descendants in document order, children are skipped if fn returns false
+func walkNodes(nodes []*Node, fn func(node *Node) bool) {
+	for _, node := range nodes {
+		if fn(node) {
+			walkNodes(node.Children, fn)
+		}
+	}
+}
+
+// findNodes returns the nodes of the given kind, searching the descendants too
+func findNodes(nodes []*Node, kind NodeKind) []*Node {
+	var found []*Node
+
+	walkNodes(nodes, func(node *Node) bool {
+		if node.Kind == kind {
+			found = append(found, node)
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// findShortcodes returns the shortcodes with one of the given names, searching the descendants too
+func findShortcodes(nodes []*Node, names ...string) []*Node {
+	var found []*Node
+
+	for _, node := range findNodes(nodes, ShortcodeNode) {
+		for _, name := range names {
+			if node.Name == name {
+				found = append(found, node)
+
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// Document is the parsed body of a markdown file
+type Document struct {
+	Nodes []*Node
+	rows  []string
+	// bodyStart is the index of the first row after the front matter
+	bodyStart int
+}
+
+// ParseDocument parses a markdown file, the front matter is skipped but it is counted in the positions
+func ParseDocument(content string) *Document {
+	rows := strings.Split(strings.Replace(content, "\r\n", EOL, -1), EOL)
+	bodyStart := frontMatterEnd(rows)
+
+	lines := make([]sourceLine, 0, len(rows)-bodyStart)
+	for i := bodyStart; i < len(rows); i++ {
+		lines = append(lines, sourceLine{text: rows[i], number: i + 1, column: 1})
+	}
+
+	return &Document{Nodes: parseBlocks(lines), rows: rows, bodyStart: bodyStart}
+}
+
+// frontMatterEnd returns the index of the first row after the TOML front matter, 0 if there is no front matter
+func frontMatterEnd(rows []string) int {
+	if len(rows) == 0 || rows[0] != "+++" {
+		return 0
+	}
+
+	for i := 1; i < len(rows); i++ {
+		if strings.TrimRight(rows[i], " \t") == "+++" {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// text returns the trimmed content of the rows between from (inclusive) and to (exclusive)
+func (d *Document) text(from, to int) string {
+	return strings.Trim(strings.Join(d.rows[from:to], EOL), " \t\n")
+}
+
+// sectionHeadings returns the indexes of the top level headings of level 2
+func (d *Document) sectionHeadings() []int {
+	var indexes []int
+
+	for i, node := range d.Nodes {
+		if node.Kind == HeadingNode && node.Level == 2 {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// Sections splits the document by its level 2 headings, the content before the first heading is kept as the root
+// section if it is not empty. Documents without level 2 headings have no sections.
+func (d *Document) Sections() Sections {
+	headings := d.sectionHeadings()
+	if len(headings) == 0 {
+		return nil
+	}
+
+	sections := make(Sections, 0, len(headings)+1)
+
+	first := d.Nodes[headings[0]]
+	if content := d.text(d.bodyStart, first.Start.Line-1); content != "" {
+		sections = append(sections, Section{Title: sectionRoot, Content: content, Nodes: d.Nodes[:headings[0]]})
+	}
+
+	for i, index := range headings {
+		heading := d.Nodes[index]
+
+		endRow, endNode := len(d.rows), len(d.Nodes)
+		if i+1 < len(headings) {
+			endNode = headings[i+1]
+			endRow = d.Nodes[endNode].Start.Line - 1
+		}
+
+		sections = append(sections, Section{
+			Title:   strings.ToLower(strings.Trim(heading.Text, " \t")),
+			Content: d.text(heading.EndLine, endRow),
+			Nodes:   d.Nodes[index+1 : endNode],
+		})
+	}
+
+	return sections
+}
+
+type sourceLine struct {
+	text string
+	// number is the line number in the file
+	number int
+	// column is the column of the first byte of text in the file
+	column int
+}
+
+func (sl sourceLine) position(offset int) Position {
+	return Position{Line: sl.number, Column: sl.column + offset}
+}
+
+func isBlank(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
+
+// indentWidth returns the width of the leading whitespace, tabs count as 4 columns
+func indentWidth(text string) int {
+	width := 0
+
+	for _, r := range text {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+
+	return width
+}
+
+// dedent removes up to width columns of leading whitespace from the line
+func dedent(line sourceLine, width int) sourceLine {
+	removed, i := 0, 0
+
+	for i < len(line.text) && removed < width {
+		switch line.text[i] {
+		case ' ':
+			removed++
+		case '\t':
+			removed += 4
+		default:
+			removed = width
+
+			continue
+		}
+
+		i++
+	}
+
+	return sourceLine{text: line.text[i:], number: line.number, column: line.column + i}
+}
+
+// openingFence returns the fence marker and the info string if the row opens a fenced code block
+func openingFence(row string) (string, string, bool) {
+	trimmed := strings.TrimLeft(row, " ")
+	if len(row)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return "", "", false
+	}
+
+	char := trimmed[0]
+	if char != '`' && char != '~' {
+		return "", "", false
+	}
+
+	length := 0
+	for length < len(trimmed) && trimmed[length] == char {
+		length++
+	}
+
+	if length < 3 {
+		return "", "", false
+	}
+
+	info := strings.TrimSpace(trimmed[length:])
+
+	// backtick fences cannot have backticks in their info string
+	if char == '`' && strings.Contains(info, "`") {
+		return "", "", false
+	}
+
+	return trimmed[:length], info, true
+}
+
+// isClosingFence returns true if the row closes a fenced code block opened with the given marker
+func isClosingFence(row, marker string) bool {
+	trimmed := strings.TrimLeft(row, " ")
+	if len(row)-len(trimmed) > 3 {
+		return false
+	}
+
+	trimmed = strings.TrimRight(trimmed, " \t")
+
+	return len(trimmed) >= len(marker) && strings.Trim(trimmed, marker[:1]) == ""
+}
+
+var (
+	regexATXHeading      = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	regexSetextUnderline = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	regexThematicBreak   = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	regexListMarker      = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])(?:([ \t]+)(.*))?$`)
+)
+
+type listMarker struct {
+	// kind is the bullet character or the delimiter of ordered lists, items of a list share the same kind
+	kind   byte
+	indent int
+	// contentIndent is the indentation of the content of the item, continuation lines are indented by it
+	contentIndent int
+	content       string
+}
+
+func parseListMarker(text string) (listMarker, bool) {
+	matches := regexListMarker.FindStringSubmatch(text)
+	if matches == nil {
+		return listMarker{}, false
+	}
+
+	marker := listMarker{kind: matches[2][len(matches[2])-1], indent: len(matches[1]), content: matches[4]}
+
+	spaces := len(matches[3])
+	if spaces == 0 || spaces > 4 || isBlank(matches[4]) {
+		spaces = 1
+	}
+
+	marker.contentIndent = marker.indent + len(matches[2]) + spaces
+
+	return marker, true
+}
+
+// startsBlock returns true for rows which cannot be the lazy continuation of a paragraph
+func startsBlock(text string) bool {
+	if _, _, ok := openingFence(text); ok {
+		return true
+	}
+
+	if _, ok := parseListMarker(text); ok {
+		return true
+	}
+
+	return regexATXHeading.MatchString(text) || regexThematicBreak.MatchString(text) ||
+		strings.HasPrefix(strings.TrimLeft(text, " "), "<!--")
+}
+
+// parseBlocks parses the block structure of the lines, inline nodes are parsed line by line
+func parseBlocks(lines []sourceLine) []*Node {
+	var (
+		nodes     []*Node
+		paragraph []sourceLine
+	)
+
+	closeParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+
+		nodes = append(nodes, newParagraph(paragraph))
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		text := line.text
+
+		if isBlank(text) {
+			closeParagraph()
+
+			continue
+		}
+
+		if indentWidth(text) >= 4 {
+			// indented code blocks cannot interrupt a paragraph
+			if len(paragraph) > 0 {
+				paragraph = append(paragraph, line)
+
+				continue
+			}
+
+			end := i + 1
+			for end < len(lines) && (isBlank(lines[end].text) || indentWidth(lines[end].text) >= 4) {
+				end++
+			}
+
+			for isBlank(lines[end-1].text) {
+				end--
+			}
+
+			nodes = append(nodes, newIndentedCode(lines[i:end]))
+			i = end - 1
+
+			continue
+		}
+
+		if marker, info, ok := openingFence(text); ok {
+			closeParagraph()
+
+			node, end := newFencedCode(lines, i, marker, info)
+			nodes = append(nodes, node)
+			i = end
+
+			continue
+		}
+
+		if offset := strings.Index(text, "<!--"); offset != -1 && isBlank(text[:offset]) {
+			closeParagraph()
+
+			node, end := newComment(lines, i, offset)
+			nodes = append(nodes, node)
+			i = end
+
+			continue
+		}
+
+		if matches := regexATXHeading.FindStringSubmatch(text); matches != nil {
+			closeParagraph()
+
+			nodes = append(nodes, &Node{
+				Kind:     HeadingNode,
+				Start:    line.position(indentWidth(text)),
+				EndLine:  line.number,
+				Level:    len(matches[1]),
+				Text:     strings.TrimSpace(matches[2]),
+				Children: parseInlines(line),
+			})
+
+			continue
+		}
+
+		if matches := regexSetextUnderline.FindStringSubmatch(text); matches != nil && len(paragraph) > 0 {
+			heading := newParagraph(paragraph)
+			heading.Kind = HeadingNode
+			heading.Level = 2
+			if matches[1][0] == '=' {
+				heading.Level = 1
+			}
+			heading.Text = strings.Replace(heading.Text, EOL, " ", -1)
+			heading.EndLine = line.number
+
+			nodes = append(nodes, heading)
+			paragraph = nil
+
+			continue
+		}
+
+		if regexThematicBreak.MatchString(text) {
+			closeParagraph()
+
+			nodes = append(nodes, &Node{Kind: ThematicBreakNode, Start: line.position(indentWidth(text)), EndLine: line.number})
+
+			continue
+		}
+
+		// empty list items cannot interrupt a paragraph
+		if marker, ok := parseListMarker(text); ok && (len(paragraph) == 0 || !isBlank(marker.content)) {
+			closeParagraph()
+
+			end := listEnd(lines, i, marker.indent)
+			nodes = append(nodes, newList(lines[i:end], marker.indent))
+			i = end - 1
+
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+
+	closeParagraph()
+
+	return nodes
+}
+
+func newParagraph(lines []sourceLine) *Node {
+	node := &Node{
+		Kind:    ParagraphNode,
+		Start:   lines[0].position(indentWidth(lines[0].text)),
+		EndLine: lines[len(lines)-1].number,
+	}
+
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		texts = append(texts, strings.TrimSpace(line.text))
+		node.Children = append(node.Children, parseInlines(line)...)
+	}
+
+	node.Text = strings.Join(texts, EOL)
+
+	return node
+}
+
+func newIndentedCode(lines []sourceLine) *Node {
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		texts = append(texts, dedent(line, 4).text)
+	}
+
+	return &Node{
+		Kind:    CodeBlockNode,
+		Start:   lines[0].position(0),
+		EndLine: lines[len(lines)-1].number,
+		Text:    strings.Join(texts, EOL),
+	}
+}
+
+// newFencedCode parses the fenced code block starting at lines[start] and returns the index of its last line
+func newFencedCode(lines []sourceLine, start int, marker, info string) (*Node, int) {
+	indent := indentWidth(lines[start].text)
+
+	node := &Node{
+		Kind:   CodeBlockNode,
+		Start:  lines[start].position(indent),
+		Fenced: true,
+	}
+
+	if fields := strings.Fields(info); len(fields) > 0 {
+		node.Language = strings.ToLower(strings.Trim(fields[0], "{}"))
+	}
+
+	end := start + 1
+	for end < len(lines) && !isClosingFence(lines[end].text, marker) {
+		end++
+	}
+
+	if end == len(lines) {
+		node.Unclosed = true
+		end--
+	}
+
+	contentEnd := end
+	if node.Unclosed {
+		contentEnd++
+	}
+
+	texts := make([]string, 0, contentEnd-start)
+	for _, line := range lines[start+1 : contentEnd] {
+		texts = append(texts, dedent(line, indent).text)
+	}
+
+	node.Text = strings.Join(texts, EOL)
+	node.EndLine = lines[end].number
+
+	return node, end
+}
+
+// newComment parses the HTML comment starting at lines[start] and returns the index of its last line
+func newComment(lines []sourceLine, start, offset int) (*Node, int) {
+	node := &Node{Kind: CommentNode, Start: lines[start].position(offset)}
+
+	end := start
+	for end < len(lines) {
+		from := 0
+		if end == start {
+			from = offset + len("<!--")
+		}
+
+		if strings.Contains(lines[end].text[from:], "-->") {
+			break
+		}
+
+		end++
+	}
+
+	if end == len(lines) {
+		node.Unclosed = true
+		end--
+	}
+
+	texts := make([]string, 0, end-start+1)
+	for _, line := range lines[start : end+1] {
+		texts = append(texts, line.text)
+	}
+
+	node.Text = strings.TrimSpace(strings.Join(texts, EOL))
+	node.EndLine = lines[end].number
+
+	return node, end
+}
+
+// listEnd returns the index of the first line after the list starting at lines[start]
+func listEnd(lines []sourceLine, start, indent int) int {
+	first, _ := parseListMarker(lines[start].text)
+	content := first.contentIndent
+
+	end := start + 1
+
+	for end < len(lines) {
+		text := lines[end].text
+
+		if isBlank(text) {
+			// blank lines are part of the list if the current item or the list continues after them
+			next := end + 1
+			for next < len(lines) && isBlank(lines[next].text) {
+				next++
+			}
+
+			if next == len(lines) {
+				break
+			}
+
+			marker, ok := parseListMarker(lines[next].text)
+			if indentWidth(lines[next].text) < content && (!ok || marker.indent != indent || marker.kind != first.kind) {
+				break
+			}
+
+			end = next
+
+			continue
+		}
+
+		if marker, ok := parseListMarker(text); ok && marker.indent == indent && marker.kind == first.kind {
+			content = marker.contentIndent
+			end++
+
+			continue
+		}
+
+		if indentWidth(text) > indent {
+			end++
+
+			continue
+		}
+
+		// lazy continuation of the last paragraph
+		if !isBlank(lines[end-1].text) && !startsBlock(text) {
+			end++
+
+			continue
+		}
+
+		break
+	}
+
+	return end
+}
+
+func newList(lines []sourceLine, indent int) *Node {
+	list := &Node{Kind: ListNode, Start: lines[0].position(indent)}
+
+	var (
+		item      *Node
+		itemLines []sourceLine
+		content   int
+	)
+
+	closeItem := func() {
+		if item == nil {
+			return
+		}
+
+		item.Children = parseBlocks(itemLines)
+		list.Children = append(list.Children, item)
+	}
+
+	for _, line := range lines {
+		if marker, ok := parseListMarker(line.text); ok && marker.indent == indent {
+			closeItem()
+
+			content = marker.contentIndent
+			item = &Node{
+				Kind:    ListItemNode,
+				Start:   line.position(indent),
+				EndLine: line.number,
+				Text:    strings.TrimSpace(marker.content),
+			}
+
+			itemLines = []sourceLine{{
+				text:   marker.content,
+				number: line.number,
+				column: line.column + len(line.text) - len(marker.content),
+			}}
+
+			continue
+		}
+
+		if !isBlank(line.text) {
+			item.EndLine = line.number
+		}
+
+		itemLines = append(itemLines, dedent(line, content))
+	}
+
+	closeItem()
+
+	list.EndLine = item.EndLine
+
+	return list
+}
+
+var (
+	regexShortcode = regexp.MustCompile(`{{([<%])\s*(/?[\w.-]+)(.*?)\s*[>%]}}`)
+	regexLink      = regexp.MustCompile(`\[([^\]]*)\]\(([^)]*)\)`)
+)
+
+// parseInlines finds the links, images and shortcodes of a line
+func parseInlines(line sourceLine) []*Node {
+	var nodes []*Node
+
+	for _, match := range regexShortcode.FindAllStringSubmatchIndex(line.text, -1) {
+		nodes = append(nodes, &Node{
+			Kind:    ShortcodeNode,
+			Start:   line.position(match[0]),
+			EndLine: line.number,
+			Text:    line.text[match[0]:match[1]],
+			Name:    line.text[match[4]:match[5]],
+			Args:    shortcodeArgs(line.text[match[6]:match[7]]),
+		})
+	}
+
+	for _, match := range regexLink.FindAllStringSubmatchIndex(line.text, -1) {
+		node := &Node{
+			Kind:        LinkNode,
+			Start:       line.position(match[0]),
+			EndLine:     line.number,
+			Text:        line.text[match[2]:match[3]],
+			Destination: linkDestination(line.text[match[4]:match[5]]),
+		}
+
+		if match[0] > 0 && line.text[match[0]-1] == '!' {
+			node.Kind = ImageNode
+			node.Start = line.position(match[0] - 1)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Start.Before(nodes[j].Start)
+	})
+
+	return nodes
+}
+
+// linkDestination removes the optional title from the destination of a link, shortcodes are kept as they are
+func linkDestination(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "{{") {
+		return raw
+	}
+
+	if destination, _, found := strings.Cut(raw, ` "`); found {
+		return destination
+	}
+
+	return raw
+}
+
+// shortcodeArgs splits the arguments of a shortcode by whitespace, keeping quoted values together
+func shortcodeArgs(raw string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+
+	for _, r := range raw {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '`':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args
+}
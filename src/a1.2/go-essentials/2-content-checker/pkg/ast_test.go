@@ -0,0 +1,147 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []*Node
+	}{
+		{
+			name:    "front matter is skipped but counted",
+			content: "+++\ntitle = 'Foo'\n+++\n\n# Foo ##\n\nBar\n===",
+			want: []*Node{
+				{Kind: HeadingNode, Start: Position{Line: 5, Column: 1}, EndLine: 5, Level: 1, Text: "Foo"},
+				{Kind: HeadingNode, Start: Position{Line: 7, Column: 1}, EndLine: 8, Level: 1, Text: "Bar"},
+			},
+		},
+		{
+			name:    "paragraph with inline nodes",
+			content: "See [Go](https://go.dev \"Go\") ![logo](logo.png)\n{{< time 5 >}} {{% youtube id=\"a b\" %}}",
+			want: []*Node{
+				{
+					Kind:    ParagraphNode,
+					Start:   Position{Line: 1, Column: 1},
+					EndLine: 2,
+					Text:    "See [Go](https://go.dev \"Go\") ![logo](logo.png)\n{{< time 5 >}} {{% youtube id=\"a b\" %}}",
+					Children: []*Node{
+						{Kind: LinkNode, Start: Position{Line: 1, Column: 5}, EndLine: 1, Text: "Go", Destination: "https://go.dev"},
+						{Kind: ImageNode, Start: Position{Line: 1, Column: 31}, EndLine: 1, Text: "logo", Destination: "logo.png"},
+						{Kind: ShortcodeNode, Start: Position{Line: 2, Column: 1}, EndLine: 2, Text: "{{< time 5 >}}", Name: "time", Args: []string{"5"}},
+						{Kind: ShortcodeNode, Start: Position{Line: 2, Column: 16}, EndLine: 2, Text: "{{% youtube id=\"a b\" %}}", Name: "youtube", Args: []string{"id=a b"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "nested lists",
+			content: "- [Foo](foo)\n  - bar\n\n  baz\n1. qux",
+			want: []*Node{
+				{
+					Kind:    ListNode,
+					Start:   Position{Line: 1, Column: 1},
+					EndLine: 4,
+					Children: []*Node{
+						{
+							Kind:    ListItemNode,
+							Start:   Position{Line: 1, Column: 1},
+							EndLine: 4,
+							Text:    "[Foo](foo)",
+							Children: []*Node{
+								{
+									Kind:    ParagraphNode,
+									Start:   Position{Line: 1, Column: 3},
+									EndLine: 1,
+									Text:    "[Foo](foo)",
+									Children: []*Node{
+										{Kind: LinkNode, Start: Position{Line: 1, Column: 3}, EndLine: 1, Text: "Foo", Destination: "foo"},
+									},
+								},
+								{
+									Kind:    ListNode,
+									Start:   Position{Line: 2, Column: 3},
+									EndLine: 2,
+									Children: []*Node{
+										{
+											Kind:    ListItemNode,
+											Start:   Position{Line: 2, Column: 3},
+											EndLine: 2,
+											Text:    "bar",
+											Children: []*Node{
+												{Kind: ParagraphNode, Start: Position{Line: 2, Column: 5}, EndLine: 2, Text: "bar"},
+											},
+										},
+									},
+								},
+								{Kind: ParagraphNode, Start: Position{Line: 4, Column: 3}, EndLine: 4, Text: "baz"},
+							},
+						},
+					},
+				},
+				{
+					Kind:    ListNode,
+					Start:   Position{Line: 5, Column: 1},
+					EndLine: 5,
+					Children: []*Node{
+						{
+							Kind:    ListItemNode,
+							Start:   Position{Line: 5, Column: 1},
+							EndLine: 5,
+							Text:    "qux",
+							Children: []*Node{
+								{Kind: ParagraphNode, Start: Position{Line: 5, Column: 4}, EndLine: 5, Text: "qux"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "code blocks, comments and thematic breaks",
+			content: "```Go {linenos=true}\n## foo\n```\n\n    bar\n\n<!-- baz\n---\n-->\n\n* * *\n\n~~~\nunclosed",
+			want: []*Node{
+				{Kind: CodeBlockNode, Start: Position{Line: 1, Column: 1}, EndLine: 3, Text: "## foo", Language: "go", Fenced: true},
+				{Kind: CodeBlockNode, Start: Position{Line: 5, Column: 1}, EndLine: 5, Text: "bar"},
+				{Kind: CommentNode, Start: Position{Line: 7, Column: 1}, EndLine: 9, Text: "<!-- baz\n---\n-->"},
+				{Kind: ThematicBreakNode, Start: Position{Line: 11, Column: 1}, EndLine: 11},
+				{Kind: CodeBlockNode, Start: Position{Line: 13, Column: 1}, EndLine: 14, Text: "unclosed", Fenced: true, Unclosed: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := ParseDocument(tt.content)
+
+			// verify
+			assert.Equal(t, tt.want, got.Nodes)
+		})
+	}
+}
+
+func Test_shortcodeArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{name: "empty", raw: "  ", want: nil},
+		{name: "positional", raw: " abc  12", want: []string{"abc", "12"}},
+		{name: "quoted", raw: `"10-foo.md" title="Foo Bar"`, want: []string{"10-foo.md", "title=Foo Bar"}},
+		{name: "raw string", raw: "`a \"b\"`", want: []string{`a "b"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := shortcodeArgs(tt.raw)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
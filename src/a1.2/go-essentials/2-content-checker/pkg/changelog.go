@@ -0,0 +1,349 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CompletionTimes returns the time of the last commit turning a content file complete, keyed by the file paths joined
+// with root. Only the commits after since are read, since is either a date or a git revision. The states are compared
+// with the states before each commit, renamed files keep their states and changes keeping a page complete (e.g.
+// re-quoting the state) are not completions.
+func CompletionTimes(root, since string) (map[string]time.Time, error) {
+	args := []string{"-C", root, "-c", "core.quotepath=off", "log", "--reverse", "--format=%x00%H %ct", "--name-status", "--find-renames", "--relative"}
+
+	var (
+		base string
+		err  error
+	)
+
+	if _, parseErr := time.Parse(time.DateOnly, since); parseErr == nil {
+		args = append(args, "--since="+since)
+		base, err = gitOutput(root, "rev-list", "-1", "--before="+since, "HEAD")
+	} else {
+		args = append(args, since+"..HEAD")
+		base, err = gitOutput(root, "rev-parse", "--verify", since+"^{commit}")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot find revision of %s since %s, err: %w", root, since, err)
+	}
+
+	output, err := exec.Command("git", append(args, "--")...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read git log of %s since %s, err: %w", root, since, err)
+	}
+
+	commits, err := parseChangedCommits(output)
+	if err != nil {
+		return nil, err
+	}
+
+	// the files are empty if nothing was committed before since
+	before := memoryFS{}
+	if base != "" {
+		if before, err = revisionFiles(root, base); err != nil {
+			return nil, err
+		}
+	}
+
+	after, err := committedFiles(root, commits)
+	if err != nil {
+		return nil, err
+	}
+
+	return completionTimes(commits, before, after, root), nil
+}
+
+func gitOutput(root string, args ...string) (string, error) {
+	output, err := exec.Command("git", append([]string{"-C", root}, args...)...).Output()
+
+	return strings.TrimSpace(string(output)), err
+}
+
+// fileChange is a content file added, modified, renamed or deleted by a commit
+type fileChange struct {
+	status   byte
+	oldPath  string
+	filePath string
+}
+
+type changedCommit struct {
+	hash    string
+	time    time.Time
+	changes []fileChange
+}
+
+// parseChangedCommits parses the output of `git log --format=%x00%H %ct --name-status`, only the changes of content
+// files are kept
+func parseChangedCommits(output []byte) ([]changedCommit, error) {
+	var commits []changedCommit
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if header, ok := strings.CutPrefix(line, "\x00"); ok {
+			hash, rawTime, _ := strings.Cut(header, " ")
+
+			seconds, err := strconv.ParseInt(rawTime, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid commit time: %s", header)
+			}
+
+			commits = append(commits, changedCommit{hash: hash, time: time.Unix(seconds, 0).UTC()})
+
+			continue
+		}
+
+		// changes are formatted as `<status>\t<file>`, renames and copies as `<status><score>\t<old file>\t<file>`
+		fields := strings.Split(line, "\t")
+		if len(commits) == 0 || len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+
+		change := fileChange{status: fields[0][0], oldPath: fields[1], filePath: fields[len(fields)-1]}
+		if !isContentFile(change.filePath) && !isContentFile(change.oldPath) {
+			continue
+		}
+
+		commits[len(commits)-1].changes = append(commits[len(commits)-1].changes, change)
+	}
+
+	return commits, scanner.Err()
+}
+
+// committedFiles returns the content files as of the commits changing them, keyed by `<commit>:<file>`
+func committedFiles(root string, commits []changedCommit) (memoryFS, error) {
+	var keys, objects []string
+
+	for _, commit := range commits {
+		for _, change := range commit.changes {
+			if change.status != 'D' && isContentFile(change.filePath) {
+				keys = append(keys, commit.hash+":"+change.filePath)
+				// the paths are relative to root, git resolves `<commit>:<file>` from the top of the repository
+				objects = append(objects, commit.hash+":./"+change.filePath)
+			}
+		}
+	}
+
+	if len(objects) == 0 {
+		return memoryFS{}, nil
+	}
+
+	cmd := exec.Command("git", "-C", root, "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(strings.Join(objects, "\n") + "\n")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read committed files in %s, err: %w", root, err)
+	}
+
+	return parseCatFileBatch(output, keys)
+}
+
+// completionTimes replays the commits, oldest first, on the states of the files before them. A file is completed by a
+// commit if its state turns complete, the last completion of every file is returned keyed by its latest path.
+func completionTimes(commits []changedCommit, before, after memoryFS, root string) map[string]time.Time {
+	states := make(map[string]State)
+
+	for filePath, rawContent := range before {
+		if isContentFile(filePath) {
+			states[filePath] = committedState(rawContent)
+		}
+	}
+
+	completionTimes := make(map[string]time.Time)
+
+	for _, commit := range commits {
+		for _, change := range commit.changes {
+			previous := states[change.filePath]
+
+			switch change.status {
+			case 'D':
+				delete(states, change.filePath)
+
+				continue
+			case 'R':
+				previous = states[change.oldPath]
+				delete(states, change.oldPath)
+
+				// a completion before the rename belongs to the renamed file
+				if completed, ok := completionTimes[filepath.Join(root, change.oldPath)]; ok {
+					delete(completionTimes, filepath.Join(root, change.oldPath))
+					completionTimes[filepath.Join(root, change.filePath)] = completed
+				}
+			}
+
+			if !isContentFile(change.filePath) {
+				continue
+			}
+
+			state := committedState(after[commit.hash+":"+change.filePath])
+			if state == Complete && previous != Complete {
+				completionTimes[filepath.Join(root, change.filePath)] = commit.time
+			}
+
+			states[change.filePath] = state
+		}
+	}
+
+	return completionTimes
+}
+
+// committedState returns the state of a committed markdown file, it is empty if the file can not be parsed
+func committedState(rawContent []byte) State {
+	content, err := ParseMarkdown(string(rawContent))
+	if err != nil {
+		return ""
+	}
+
+	return content.State
+}
+
+type CompletedPage struct {
+	FilePath  string
+	Title     string
+	Course    string
+	Chapter   string
+	URL       string
+	Completed time.Time
+}
+
+// Changelog lists the completed pages in the order of the courses
+type Changelog []CompletedPage
+
+// Changelog returns the complete pages which have a completion time, translations and index pages are left out. The
+// URLs of the pages are made of the base URL, the course, the chapter and the slug of the page.
+func (c Courses) Changelog(completionTimes map[string]time.Time, baseURL string) Changelog {
+	var changelog Changelog
+
+	for _, course := range c {
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				if page.IsIndex() || page.GetState() != Complete {
+					continue
+				}
+
+				completed, ok := completionTimes[page.FilePath]
+				if !ok {
+					continue
+				}
+
+				slug := page.Content.Slug
+				if slug == "" {
+					slug = strings.TrimSuffix(filepath.Base(page.FilePath), ".md")
+				}
+
+				changelog = append(changelog, CompletedPage{
+					FilePath:  page.FilePath,
+					Title:     page.Content.Title,
+					Course:    course.Title,
+					Chapter:   chapter.Title,
+					URL:       fmt.Sprintf("%s/%s/%s/%s/", strings.TrimRight(baseURL, "/"), course.Title, chapter.Title, slug),
+					Completed: completed,
+				})
+			}
+		}
+	}
+
+	return changelog
+}
+
+// Markdown returns the changelog grouped by course and chapter
+func (cl Changelog) Markdown(title string) string {
+	var sb strings.Builder
+
+	sb.WriteString("# " + title + EOL)
+
+	if len(cl) == 0 {
+		sb.WriteString(EOL + "No lessons were completed." + EOL)
+	}
+
+	for i, page := range cl {
+		if i == 0 || page.Course != cl[i-1].Course {
+			sb.WriteString(fmt.Sprintf("\n## %s\n", page.Course))
+		}
+
+		if i == 0 || page.Course != cl[i-1].Course || page.Chapter != cl[i-1].Chapter {
+			sb.WriteString(fmt.Sprintf("\n### %s\n\n", page.Chapter))
+		}
+
+		sb.WriteString(fmt.Sprintf("- [%s](%s) (%s)\n", page.Title, page.URL, page.Completed.Format(time.DateOnly)))
+	}
+
+	return sb.String()
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Link    atomLink `xml:"link"`
+	Updated string   `xml:"updated"`
+	Summary string   `xml:"summary"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Author  string      `xml:"author>name"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// Atom returns the changelog as an Atom feed, the newest completion is used as the time the feed was updated at, the
+// current time for empty changelogs
+func (cl Changelog) Atom(title, baseURL, author string) (string, error) {
+	baseURL = strings.TrimRight(baseURL, "/") + "/"
+
+	feed := atomFeed{
+		Title:  title,
+		ID:     baseURL,
+		Link:   atomLink{Href: baseURL},
+		Author: author,
+	}
+
+	var updated time.Time
+
+	for _, page := range cl {
+		if page.Completed.After(updated) {
+			updated = page.Completed
+		}
+
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   page.Title,
+			ID:      page.URL,
+			Link:    atomLink{Href: page.URL},
+			Updated: page.Completed.Format(time.RFC3339),
+			Summary: fmt.Sprintf("%s completed in %s / %s", page.Title, page.Course, page.Chapter),
+		})
+	}
+
+	if updated.IsZero() {
+		updated = time.Now().UTC()
+	}
+
+	feed.Updated = updated.Format(time.RFC3339)
+
+	raw, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(raw) + EOL, nil
+}
@@ -0,0 +1,240 @@
+package pkg
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseChangedCommits(t *testing.T) {
+	output := "\x00abc 1711929600\n\nA\tcontent/a1/go/10-foo.md\nM\tREADME.md\n" +
+		"\x00def 1714521600\n\nR095\tcontent/a1/go/10-foo.md\tcontent/a1/go/15-foo.md\nD\tcontent/a1/go/30-baz.md\n" +
+		"\x00fed 1714608000\n\nM\tstatic/foo.md\n"
+
+	// execute
+	got, err := parseChangedCommits([]byte(output))
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []changedCommit{
+		{
+			hash:    "abc",
+			time:    time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			changes: []fileChange{{status: 'A', oldPath: "content/a1/go/10-foo.md", filePath: "content/a1/go/10-foo.md"}},
+		},
+		{
+			hash: "def",
+			time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			changes: []fileChange{
+				{status: 'R', oldPath: "content/a1/go/10-foo.md", filePath: "content/a1/go/15-foo.md"},
+				{status: 'D', oldPath: "content/a1/go/30-baz.md", filePath: "content/a1/go/30-baz.md"},
+			},
+		},
+		{hash: "fed", time: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+	}, got)
+
+	_, err = parseChangedCommits([]byte("\x00abc now\n"))
+	require.ErrorContains(t, err, "invalid commit time: abc now")
+}
+
+func Test_completionTimes(t *testing.T) {
+	page := func(state string) []byte {
+		return []byte("+++\ntitle = 'Foo'\nstate = " + state + "\n+++\n\n## Summary\n")
+	}
+
+	first := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	before := memoryFS{
+		"content/a1/go/10-done.md":    page("'complete'"),
+		"content/a1/go/20-renamed.md": page("'complete'"),
+		"content/a1/go/30-draft.md":   page("'incomplete'"),
+		"content/a1/go/40-again.md":   page("'complete'"),
+	}
+
+	commits := []changedCommit{
+		{hash: "abc", time: first, changes: []fileChange{
+			{status: 'M', oldPath: "content/a1/go/10-done.md", filePath: "content/a1/go/10-done.md"},
+			{status: 'R', oldPath: "content/a1/go/20-renamed.md", filePath: "content/a1/go/25-renamed.md"},
+			{status: 'A', oldPath: "content/a1/go/50-new.md", filePath: "content/a1/go/50-new.md"},
+			{status: 'M', oldPath: "content/a1/go/40-again.md", filePath: "content/a1/go/40-again.md"},
+		}},
+		{hash: "def", time: second, changes: []fileChange{
+			{status: 'M', oldPath: "content/a1/go/30-draft.md", filePath: "content/a1/go/30-draft.md"},
+			{status: 'M', oldPath: "content/a1/go/40-again.md", filePath: "content/a1/go/40-again.md"},
+			{status: 'D', oldPath: "content/a1/go/50-new.md", filePath: "content/a1/go/50-new.md"},
+		}},
+		{hash: "fed", time: second.Add(time.Hour), changes: []fileChange{
+			{status: 'R', oldPath: "content/a1/go/30-draft.md", filePath: "content/a1/go/35-draft.md"},
+		}},
+	}
+
+	after := memoryFS{
+		"abc:content/a1/go/10-done.md":    page("\"complete\""),
+		"abc:content/a1/go/25-renamed.md": page("'complete'"),
+		"abc:content/a1/go/50-new.md":     page("'complete'"),
+		"abc:content/a1/go/40-again.md":   page("'stub'"),
+		"def:content/a1/go/30-draft.md":   page("'complete'"),
+		"def:content/a1/go/40-again.md":   page("'complete'"),
+		"fed:content/a1/go/35-draft.md":   page("\"complete\""),
+	}
+
+	// execute
+	got := completionTimes(commits, before, after, "site")
+
+	// verify
+	assert.Equal(t, map[string]time.Time{
+		"site/content/a1/go/50-new.md":   first,
+		"site/content/a1/go/35-draft.md": second,
+		"site/content/a1/go/40-again.md": second,
+	}, got)
+}
+
+func TestCompletionTimes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// the site is in a subdirectory of the repository
+	repository := t.TempDir()
+	root := filepath.Join(repository, "site")
+
+	git := func(date string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repository}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	write := func(filePath, state string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+		require.NoError(t, os.WriteFile(filePath, []byte("+++\ntitle = 'Foo'\nstate = "+state+"\n+++\n"), 0o644))
+	}
+
+	git("", "init", "--quiet")
+	git("", "config", "user.email", "peet@example.com")
+	git("", "config", "user.name", "Peet")
+
+	write(filepath.Join(root, "content/a1/go/10-foo.md"), "'stub'")
+	write(filepath.Join(root, "content/a1/go/20-bar.md"), "'complete'")
+	git("2024-04-01T00:00:00Z", "add", "--all")
+	git("2024-04-01T00:00:00Z", "commit", "--quiet", "--message", "add pages")
+	git("", "tag", "base")
+
+	write(filepath.Join(root, "content/a1/go/10-foo.md"), "'complete'")
+	git("2024-05-01T00:00:00Z", "commit", "--quiet", "--all", "--message", "complete foo")
+
+	git("2024-05-02T00:00:00Z", "mv", "site/content/a1/go/20-bar.md", "site/content/a1/go/25-bar.md")
+	write(filepath.Join(root, "content/a1/go/25-bar.md"), `"complete"`)
+	git("2024-05-02T00:00:00Z", "commit", "--quiet", "--all", "--message", "rename bar")
+
+	for _, since := range []string{"base", "2024-04-15", "2000-01-01"} {
+		t.Run(since, func(t *testing.T) {
+			want := map[string]time.Time{
+				filepath.Join(root, "content/a1/go/10-foo.md"): time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			// the renamed page was complete before the first commit read
+			if since == "2000-01-01" {
+				want[filepath.Join(root, "content/a1/go/25-bar.md")] = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+			}
+
+			// execute
+			got, err := CompletionTimes(root, since)
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestCourses_Changelog(t *testing.T) {
+	completed := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	courses := testCourses(
+		Page{FilePath: "site/content/a1/go/_index.md", Content: Content{Title: "Go", State: Complete, Body: &IndexBody{}}},
+		testPage("site/content/a1/go/10-foo.md", "foo", Complete),
+		Page{FilePath: "site/content/a1/go/20-bar.md", Content: Content{Title: "Bar", State: Complete, Body: DefaultBody{}}},
+		testPage("site/content/a1/go/30-baz.md", "baz", Incomplete),
+		testPage("site/content/a1/go/40-qux.md", "qux", Complete),
+		testPage("site/content/b1/rust/10-cargo.md", "cargo", Complete),
+	)
+
+	completionTimes := map[string]time.Time{
+		"site/content/a1/go/_index.md":      completed,
+		"site/content/a1/go/10-foo.md":      completed,
+		"site/content/a1/go/20-bar.md":      completed,
+		"site/content/a1/go/30-baz.md":      completed,
+		"site/content/b1/rust/10-cargo.md":  completed.Add(time.Hour),
+		"site/content/b1/rust/20-unsafe.md": completed,
+	}
+
+	// execute
+	got := courses.Changelog(completionTimes, "https://example.com/")
+
+	// verify
+	assert.Equal(t, Changelog{
+		{FilePath: "site/content/a1/go/10-foo.md", Title: "foo", Course: "a1", Chapter: "go", URL: "https://example.com/a1/go/foo/", Completed: completed},
+		{FilePath: "site/content/a1/go/20-bar.md", Title: "Bar", Course: "a1", Chapter: "go", URL: "https://example.com/a1/go/20-bar/", Completed: completed},
+		{FilePath: "site/content/b1/rust/10-cargo.md", Title: "cargo", Course: "b1", Chapter: "rust", URL: "https://example.com/b1/rust/cargo/", Completed: completed.Add(time.Hour)},
+	}, got)
+}
+
+func TestChangelog_Markdown(t *testing.T) {
+	completed := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		changelog Changelog
+		want      string
+	}{
+		{
+			name:      "empty",
+			changelog: nil,
+			want:      "# May\n\nNo lessons were completed.\n",
+		},
+		{
+			name: "grouped by course and chapter",
+			changelog: Changelog{
+				{Title: "Foo", Course: "a1", Chapter: "go", URL: "https://example.com/a1/go/foo/", Completed: completed},
+				{Title: "Bar", Course: "a1", Chapter: "go", URL: "https://example.com/a1/go/bar/", Completed: completed},
+				{Title: "Cargo", Course: "b1", Chapter: "rust", URL: "https://example.com/b1/rust/cargo/", Completed: completed},
+			},
+			want: "# May\n\n## a1\n\n### go\n\n- [Foo](https://example.com/a1/go/foo/) (2024-05-01)\n- [Bar](https://example.com/a1/go/bar/) (2024-05-01)\n\n## b1\n\n### rust\n\n- [Cargo](https://example.com/b1/rust/cargo/) (2024-05-01)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := tt.changelog.Markdown("May")
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChangelog_Atom(t *testing.T) {
+	changelog := Changelog{
+		{Title: "Foo & Bar", Course: "a1", Chapter: "go", URL: "https://example.com/a1/go/foo/", Completed: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{Title: "Cargo", Course: "b1", Chapter: "rust", URL: "https://example.com/b1/rust/cargo/", Completed: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)},
+	}
+
+	// execute
+	got, err := changelog.Atom("May", "https://example.com", "Peet")
+
+	// verify
+	require.NoError(t, err)
+	assert.Contains(t, got, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, got, "<id>https://example.com/</id>")
+	assert.Contains(t, got, "<updated>2024-05-02T10:00:00Z</updated>\n  <author>\n    <name>Peet</name>")
+	assert.Contains(t, got, "<title>Foo &amp; Bar</title>")
+	assert.Contains(t, got, `<link href="https://example.com/b1/rust/cargo/"></link>`)
+}
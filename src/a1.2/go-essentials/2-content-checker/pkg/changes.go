@@ -0,0 +1,183 @@
+package pkg
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type FileChange struct {
+	FilePath    string
+	NewFilePath string
+	Before      string
+	After       string
+}
+
+func (fc FileChange) IsRename() bool {
+	return fc.NewFilePath != "" && fc.NewFilePath != fc.FilePath
+}
+
+type FileChanges []FileChange
+
+// Diff returns a human-readable description of the changes, showing renames and changed lines
+func (fc FileChanges) Diff() string {
+	var sb strings.Builder
+
+	for _, change := range fc {
+		if change.IsRename() {
+			sb.WriteString(fmt.Sprintf("rename %s -> %s\n", change.FilePath, change.NewFilePath))
+		}
+
+		if change.Before == change.After {
+			continue
+		}
+
+		newFilePath := change.FilePath
+		if change.IsRename() {
+			newFilePath = change.NewFilePath
+		}
+
+		sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", change.FilePath, newFilePath))
+		sb.WriteString(diffLines(strings.Split(change.Before, EOL), strings.Split(change.After, EOL)))
+	}
+
+	return sb.String()
+}
+
+// diffLines returns the changed lines between two versions of a file, grouped into hunks without context lines
+func diffLines(before, after []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of before[i:] and after[j:]
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var (
+		sb             strings.Builder
+		removed, added []string
+		hunkStart      int
+	)
+
+	flush := func() {
+		if len(removed) == 0 && len(added) == 0 {
+			return
+		}
+
+		sb.WriteString(fmt.Sprintf("@@ line %d @@\n", hunkStart+1))
+
+		for _, line := range removed {
+			sb.WriteString("-" + line + EOL)
+		}
+
+		for _, line := range added {
+			sb.WriteString("+" + line + EOL)
+		}
+
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			flush()
+			i++
+			j++
+
+			continue
+		case j >= len(after) || i < len(before) && lcs[i+1][j] >= lcs[i][j+1]:
+			if len(removed) == 0 && len(added) == 0 {
+				hunkStart = i
+			}
+
+			removed = append(removed, before[i])
+			i++
+		default:
+			if len(removed) == 0 && len(added) == 0 {
+				hunkStart = i
+			}
+
+			added = append(added, after[j])
+			j++
+		}
+	}
+
+	flush()
+
+	return sb.String()
+}
+
+// Apply writes the changes to disk. Renames happen in two steps so that swapped file names do not overwrite each other.
+func (fc FileChanges) Apply() error {
+	for _, change := range fc {
+		if change.Before == change.After {
+			continue
+		}
+
+		if err := os.WriteFile(change.FilePath, []byte(change.After), 0o644); err != nil {
+			return fmt.Errorf("cannot write file: %s, err: %w", change.FilePath, err)
+		}
+	}
+
+	const tmpSuffix = ".mdcheck-tmp"
+
+	for _, change := range fc {
+		if !change.IsRename() {
+			continue
+		}
+
+		if err := os.Rename(change.FilePath, change.FilePath+tmpSuffix); err != nil {
+			return fmt.Errorf("cannot rename file: %s, err: %w", change.FilePath, err)
+		}
+	}
+
+	for _, change := range fc {
+		if !change.IsRename() {
+			continue
+		}
+
+		if err := os.Rename(change.FilePath+tmpSuffix, change.NewFilePath); err != nil {
+			return fmt.Errorf("cannot rename file: %s, err: %w", change.FilePath, err)
+		}
+	}
+
+	return nil
+}
+
+// ReadMarkdownFiles reads every markdown file found under root, keyed by their paths
+func ReadMarkdownFiles(root string) (map[string]string, error) {
+	files := make(map[string]string)
+
+	err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(filePath) != ".md" {
+			return nil
+		}
+
+		rawContent, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		files[filePath] = string(rawContent)
+
+		return nil
+	})
+
+	return files, err
+}
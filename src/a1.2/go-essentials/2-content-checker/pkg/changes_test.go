@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileChanges_Diff(t *testing.T) {
+	changes := FileChanges{
+		{
+			FilePath:    "foo/20-bar.md",
+			NewFilePath: "foo/30-bar.md",
+			Before:      "+++\nweight = 20\n+++\n",
+			After:       "+++\nweight = 30\n+++\n",
+		},
+		{
+			FilePath: "foo/_index.md",
+			Before:   "a\nb\nc\nd\n",
+			After:    "a\nc\nx\ny\nd\n",
+		},
+	}
+
+	want := `rename foo/20-bar.md -> foo/30-bar.md
+--- foo/20-bar.md
++++ foo/30-bar.md
+@@ line 2 @@
+-weight = 20
++weight = 30
+--- foo/_index.md
++++ foo/_index.md
+@@ line 2 @@
+-b
+@@ line 4 @@
++x
++y
+`
+
+	// execute
+	got := changes.Diff()
+
+	// verify
+	assert.Equal(t, want, got)
+}
+
+func TestFileChanges_Apply(t *testing.T) {
+	dir := t.TempDir()
+
+	fooPath := filepath.Join(dir, "10-foo.md")
+	barPath := filepath.Join(dir, "20-bar.md")
+
+	require.NoError(t, os.WriteFile(fooPath, []byte(reorderTestPage("10", "foo")), 0o644))
+	require.NoError(t, os.WriteFile(barPath, []byte(reorderTestPage("20", "bar")), 0o644))
+
+	files, err := ReadMarkdownFiles(dir)
+	require.NoError(t, err)
+
+	plan, err := PlanReorder(files, dir, ReorderOperation{Move: "bar"})
+	require.NoError(t, err)
+
+	// execute
+	err = plan.Apply()
+	require.NoError(t, err)
+
+	// verify
+	got, err := ReadMarkdownFiles(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		fooPath:                        reorderTestPage("10", "foo"),
+		filepath.Join(dir, "5-bar.md"): reorderTestPage("5", "bar"),
+	}, got)
+}
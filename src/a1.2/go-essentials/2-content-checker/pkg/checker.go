@@ -0,0 +1,534 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const contentDir = "content"
+
+// Checker crawls the markdown files of a site and runs the checks on them. The zero value is not usable, use
+// NewChecker instead.
+type Checker struct {
+	root         string
+	fsys         fs.FS
+	gitHistory   bool
+	commitTimes  map[string]time.Time
+	config       *Config
+	sectionRules map[string]SectionRule
+	vocabulary   *TagVocabulary
+	documents    map[string]string
+	maxErrors    int
+	concurrency  int
+}
+
+type Option func(c *Checker)
+
+// WithRoot sets the directory of the site, the site is read from the disk and the ages of translations are checked
+// using its git history. File paths are reported joined with the root.
+func WithRoot(root string) Option {
+	return func(c *Checker) {
+		c.root = root
+		c.fsys = os.DirFS(root)
+		c.gitHistory = true
+	}
+}
+
+// WithFS sets the file system the site is read from, the ages of translations are not checked. A root set before is
+// only used to report the file paths.
+func WithFS(fsys fs.FS) Option {
+	return func(c *Checker) {
+		c.fsys = fsys
+		c.gitHistory = false
+	}
+}
+
+// WithCommitTimes sets the commit times the ages of translations are checked with instead of reading the git history
+// of the site, keyed like the result of CommitTimes
+func WithCommitTimes(commitTimes map[string]time.Time) Option {
+	return func(c *Checker) {
+		c.commitTimes = commitTimes
+	}
+}
+
+// WithConfig sets the config instead of loading it from the config file of the site
+func WithConfig(config Config) Option {
+	return func(c *Checker) {
+		c.config = &config
+	}
+}
+
+// WithSectionRules replaces the section rules of the config, keyed by the lowercase section titles
+func WithSectionRules(rules map[string]SectionRule) Option {
+	return func(c *Checker) {
+		c.sectionRules = rules
+	}
+}
+
+// WithTagVocabulary sets the tag vocabulary instead of loading it from the vocabulary file of the site
+func WithTagVocabulary(vocabulary TagVocabulary) Option {
+	return func(c *Checker) {
+		c.vocabulary = &vocabulary
+	}
+}
+
+// WithDocuments sets documents replacing the files of the site, keyed by the reported file paths. Documents under a
+// content directory are checked even if they are not saved yet.
+func WithDocuments(documents map[string]string) Option {
+	return func(c *Checker) {
+		c.documents = documents
+	}
+}
+
+// WithMaxErrors stops crawling after the given number of pages with issues, zero or less means no limit
+func WithMaxErrors(maxErrors int) Option {
+	return func(c *Checker) {
+		c.maxErrors = maxErrors
+	}
+}
+
+// WithConcurrency sets the number of files parsed at the same time, it defaults to the number of CPUs
+func WithConcurrency(concurrency int) Option {
+	return func(c *Checker) {
+		c.concurrency = max(concurrency, 1)
+	}
+}
+
+func NewChecker(options ...Option) *Checker {
+	c := &Checker{concurrency: runtime.NumCPU()}
+
+	WithRoot(".")(c)
+
+	for _, option := range options {
+		option(c)
+	}
+
+	// documents are looked up by their paths in the file system, documents outside of the root are never read
+	documents := make(map[string]string, len(c.documents))
+
+	for reportedPath, content := range c.documents {
+		if filePath, err := c.fsPath(reportedPath); err == nil {
+			documents[filePath] = content
+		}
+	}
+
+	c.documents = documents
+
+	return c
+}
+
+// Result contains the checked courses
+type Result struct {
+	Courses Courses
+	// Count is the number of crawled markdown files
+	Count int
+	// Skipped contains the given files which are not pages of a content directory
+	Skipped []string
+	// Warnings contains the problems preventing some of the checks
+	Warnings   []string
+	Vocabulary TagVocabulary
+	Config     Config
+
+	documentsOnly bool
+}
+
+// Errors returns every issue found, prefixed by the path of its file
+func (r Result) Errors() []string {
+	var errors []string
+
+	for _, course := range r.Courses {
+		if r.documentsOnly {
+			errors = append(errors, course.GetDocumentErrors()...)
+		} else {
+			errors = append(errors, course.GetErrors()...)
+		}
+	}
+
+	return errors
+}
+
+// Files returns the markdown files of the site: the course index pages first, then the chapter pages. Translations are
+// found either next to their source pages or in per-language content directories (content.pl).
+func (c *Checker) Files() ([]string, error) {
+	files, err := c.find()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, filePath := range files {
+		files[i] = c.reportedPath(filePath)
+	}
+
+	return files, nil
+}
+
+func (c *Checker) find() ([]string, error) {
+	var courseIndexes, pages []string
+
+	for _, dir := range []string{contentDir, contentDir + ".*"} {
+		matches, err := fs.Glob(c.fsys, path.Join(dir, "*", "_index*.md"))
+		if err != nil {
+			return nil, err
+		}
+
+		courseIndexes = append(courseIndexes, matches...)
+
+		matches, err = fs.Glob(c.fsys, path.Join(dir, "*", "*", "*.md"))
+		if err != nil {
+			return nil, err
+		}
+
+		pages = append(pages, matches...)
+	}
+
+	return append(courseIndexes, pages...), nil
+}
+
+// Check crawls and checks the whole site
+func (c *Checker) Check() (Result, error) {
+	files, err := c.find()
+	if err != nil {
+		return Result{}, fmt.Errorf("cannot find files in root: %s, error: %w", c.root, err)
+	}
+
+	files = append(files, c.unsavedDocuments(files)...)
+
+	result, err := c.crawl(files)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if err := c.check(&result); err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// unsavedDocuments returns the documents which are pages of a content directory, but not found in the file system
+func (c *Checker) unsavedDocuments(files []string) []string {
+	found := make(map[string]struct{}, len(files))
+	for _, filePath := range files {
+		found[filePath] = struct{}{}
+	}
+
+	var unsaved []string
+
+	for filePath := range c.documents {
+		if _, ok := found[filePath]; ok || path.Ext(filePath) != ".md" {
+			continue
+		}
+
+		if segments := strings.Split(filePath, "/"); len(segments) < 3 || !isContentDir(segments[0]) {
+			continue
+		}
+
+		unsaved = append(unsaved, filePath)
+	}
+
+	return unsaved
+}
+
+// CheckDocuments checks the given files only, the checks needing whole chapters or courses are skipped. Course,
+// chapter and page are derived from the file paths, files which are not pages of a content directory are skipped.
+func (c *Checker) CheckDocuments(reportedPaths []string) (Result, error) {
+	var (
+		files   []string
+		skipped []string
+	)
+
+	for _, reportedPath := range reportedPaths {
+		filePath, err := c.fsPath(reportedPath)
+		if err != nil || !isContentFile(filePath) {
+			skipped = append(skipped, reportedPath)
+			continue
+		}
+
+		files = append(files, filePath)
+	}
+
+	result, err := c.crawl(files)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Skipped = append(skipped, result.Skipped...)
+	result.documentsOnly = true
+
+	if result.Vocabulary, err = c.loadTagVocabulary(); err != nil {
+		return Result{}, err
+	}
+
+	if result.Config, err = c.loadConfig(); err != nil {
+		return Result{}, err
+	}
+
+	if !result.Vocabulary.IsEmpty() {
+		result.Courses.CheckTags(result.Vocabulary)
+	}
+
+	result.Courses.CheckSections(result.Config)
+
+	return result, nil
+}
+
+// isContentDir returns true for the content directory and the per-language content directories
+func isContentDir(dir string) bool {
+	return dir == contentDir || strings.HasPrefix(dir, contentDir+".")
+}
+
+// isContentFile returns true for the course index pages and chapter pages of a content directory
+func isContentFile(filePath string) bool {
+	parts := strings.Split(filePath, "/")
+
+	if fileName, _ := SplitLanguage(parts[len(parts)-1]); fileName == indexFileName && len(parts) >= 3 && isContentDir(parts[len(parts)-3]) {
+		return true
+	}
+
+	return len(parts) >= 4 && isContentDir(parts[len(parts)-4]) && path.Ext(filePath) == ".md"
+}
+
+// reportedPath returns the path of a file of the file system joined with the root
+func (c *Checker) reportedPath(filePath string) string {
+	return filepath.Join(c.root, filepath.FromSlash(filePath))
+}
+
+// fsPath returns the path of a reported file in the file system
+func (c *Checker) fsPath(reportedPath string) (string, error) {
+	root := c.root
+
+	if filepath.IsAbs(reportedPath) {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return "", err
+		}
+
+		root = absRoot
+	}
+
+	filePath, err := filepath.Rel(root, reportedPath)
+	if err != nil {
+		return "", err
+	}
+
+	filePath = filepath.ToSlash(filePath)
+	if !fs.ValidPath(filePath) {
+		return "", fmt.Errorf("file is outside of the root: %s", reportedPath)
+	}
+
+	return filePath, nil
+}
+
+func (c *Checker) readFile(filePath string) ([]byte, error) {
+	if content, ok := c.documents[filePath]; ok {
+		return []byte(content), nil
+	}
+
+	return fs.ReadFile(c.fsys, filePath)
+}
+
+type parsedFile struct {
+	content Content
+	err     error
+}
+
+// crawl parses the files concurrently and adds them to the courses in the given order
+func (c *Checker) crawl(files []string) (Result, error) {
+	parsed := make([]parsedFile, len(files))
+
+	var wg sync.WaitGroup
+
+	indexes := make(chan int)
+
+	for range min(c.concurrency, len(files)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				parsed[i] = c.parseFile(files[i])
+			}
+		}()
+	}
+
+	for i := range files {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	result := Result{Courses: make(Courses, 0, len(files))}
+
+	var errCount int
+
+	for i, filePath := range files {
+		if c.maxErrors > 0 && errCount >= c.maxErrors {
+			break
+		}
+
+		if parsed[i].err != nil {
+			return Result{}, parsed[i].err
+		}
+
+		parts := strings.Split(filePath, "/")
+
+		if len(parts) < 3 {
+			result.Skipped = append(result.Skipped, c.reportedPath(filePath))
+			continue
+		}
+
+		course := parts[len(parts)-3]
+		chapter := parts[len(parts)-2]
+		page := parts[len(parts)-1]
+
+		reportedPath := c.reportedPath(filePath)
+		content := parsed[i].content
+
+		// course index pages are found directly in the content directory and validated by their courses
+		if fileName, _ := SplitLanguage(page); isContentDir(course) && fileName == indexFileName {
+			result.Courses = result.Courses.AddIndex(reportedPath, chapter, content)
+			result.Count++
+
+			continue
+		}
+
+		result.Courses = result.Courses.Add(reportedPath, course, chapter, page, content)
+
+		if len(content.GetIssues(reportedPath)) > 0 {
+			errCount++
+		}
+
+		result.Count++
+	}
+
+	return result, nil
+}
+
+func (c *Checker) parseFile(filePath string) parsedFile {
+	rawContent, err := c.readFile(filePath)
+	if err != nil {
+		return parsedFile{err: fmt.Errorf("cannot open file: %s, err: %w", c.reportedPath(filePath), err)}
+	}
+
+	content, err := ParseMarkdown(string(rawContent))
+	if err != nil {
+		return parsedFile{err: fmt.Errorf("cannot parse markdown: %s, err: %w", c.reportedPath(filePath), err)}
+	}
+
+	return parsedFile{content: content}
+}
+
+// check prepares the courses and runs the checks which need to know the whole site
+func (c *Checker) check(result *Result) error {
+	for i := range result.Courses {
+		result.Courses[i].Prepare()
+	}
+
+	var err error
+
+	if result.Vocabulary, err = c.loadTagVocabulary(); err != nil {
+		return err
+	}
+
+	if !result.Vocabulary.IsEmpty() {
+		result.Courses.CheckTags(result.Vocabulary)
+	}
+
+	result.Courses.CheckPrerequisites()
+
+	if result.Config, err = c.loadConfig(); err != nil {
+		return err
+	}
+
+	result.Courses.CheckSections(result.Config)
+
+	languages := result.Config.Languages
+	if len(languages) == 0 {
+		languages = result.Courses.Languages()
+	}
+
+	if len(languages) == 0 {
+		return nil
+	}
+
+	commitTimes := c.commitTimes
+
+	if commitTimes == nil && c.gitHistory {
+		commitTimes, err = CommitTimes(c.root)
+		if err != nil {
+			result.Warnings = append(result.Warnings, "translation ages are not checked: "+err.Error())
+		}
+	}
+
+	result.Courses.CheckTranslations(languages, commitTimes)
+
+	return nil
+}
+
+// TagVocabulary returns the tag vocabulary of the site, it is empty if the site has none
+func (c *Checker) TagVocabulary() (TagVocabulary, error) {
+	return c.loadTagVocabulary()
+}
+
+func (c *Checker) loadTagVocabulary() (TagVocabulary, error) {
+	if c.vocabulary != nil {
+		return *c.vocabulary, nil
+	}
+
+	rawContent, err := fs.ReadFile(c.fsys, TagVocabularyFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return TagVocabulary{}, nil
+	} else if err != nil {
+		return TagVocabulary{}, fmt.Errorf("cannot open file: %s, err: %w", c.reportedPath(TagVocabularyFileName), err)
+	}
+
+	vocabulary, err := ParseTagVocabulary(string(rawContent))
+	if err != nil {
+		return TagVocabulary{}, fmt.Errorf("cannot parse tag vocabulary: %s, err: %w", c.reportedPath(TagVocabularyFileName), err)
+	}
+
+	return vocabulary, nil
+}
+
+func (c *Checker) loadConfig() (Config, error) {
+	config, err := c.loadConfigFile()
+	if err != nil {
+		return Config{}, err
+	}
+
+	if c.sectionRules != nil {
+		config.Sections = c.sectionRules
+	}
+
+	return config, nil
+}
+
+func (c *Checker) loadConfigFile() (Config, error) {
+	if c.config != nil {
+		return *c.config, nil
+	}
+
+	rawContent, err := fs.ReadFile(c.fsys, ConfigFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Config{}, nil
+	} else if err != nil {
+		return Config{}, fmt.Errorf("cannot open file: %s, err: %w", c.reportedPath(ConfigFileName), err)
+	}
+
+	config, err := ParseConfig(rawContent)
+	if err != nil {
+		return Config{}, fmt.Errorf("cannot parse config: %s, err: %w", c.reportedPath(ConfigFileName), err)
+	}
+
+	return config, nil
+}
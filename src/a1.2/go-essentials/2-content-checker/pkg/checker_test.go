@@ -0,0 +1,183 @@
+package pkg
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	checkerTestCourseIndex = "+++\ntitle = 'A1'\ndescription = 'Basics'\naudience = \"all\"\n+++\n"
+	checkerTestIndex       = "+++\ntitle = 'Go'\nweight = 10\n+++\n\n## Episodes\n\n- [Foo]({{< ref \"10-foo.md\" >}})\n"
+	checkerTestPage        = "+++\ntitle = 'Foo'\nslug = 'foo'\nweight = 10\nstate = 'stub'\naudience = \"all\"\naudienceImportance = \"important\"\ntags = [\"go\"]\n+++\n\n## Summary\n\n- foo\n\n## Topics\n\n- bar\n"
+)
+
+func checkerTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"content/a1/_index.md":        {Data: []byte(checkerTestCourseIndex)},
+		"content/a1/go/_index.md":     {Data: []byte(checkerTestIndex)},
+		"content/a1/go/10-foo.md":     {Data: []byte(checkerTestPage)},
+		"content/a1/go/20-bar.md":     {Data: []byte("+++\ntitle = 'Bar'\nslug = 'bar'\nweight = 10\nstate = 'stub'\naudience = \"all\"\naudienceImportance = \"important\"\ntags = [\"goroutine\"]\n+++\n\n## Summary\n\n- bar\n\n## Topics\n\n- baz\n")},
+		"content/a1/go/notes.txt":     {Data: []byte("not markdown")},
+		"content/a1/rust/10-baz.md":   {Data: []byte("+++\ntitle = 'Baz'\nslug = 'baz'\nweight = 10\n+++\n")},
+		"tags.txt":                    {Data: []byte("go\ngoroutines: goroutine\n")},
+		"mdcheck.json":                {Data: []byte(`{"sections": {"summary": {"minWords": 2}}}`)},
+		"content/a1/go/30-qux.tmp.md": {Data: []byte(checkerTestPage)},
+	}
+}
+
+func TestChecker_Files(t *testing.T) {
+	checker := NewChecker(WithFS(checkerTestFS()))
+
+	// execute
+	got, err := checker.Files()
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"content/a1/_index.md",
+		"content/a1/go/10-foo.md",
+		"content/a1/go/20-bar.md",
+		"content/a1/go/30-qux.tmp.md",
+		"content/a1/go/_index.md",
+		"content/a1/rust/10-baz.md",
+	}, got)
+}
+
+func TestChecker_Check(t *testing.T) {
+	fsys := checkerTestFS()
+	delete(fsys, "content/a1/go/30-qux.tmp.md")
+
+	t.Run("whole site", func(t *testing.T) {
+		checker := NewChecker(WithFS(fsys), WithConcurrency(2))
+
+		// execute
+		got, err := checker.Check()
+
+		// verify
+		require.NoError(t, err)
+		assert.Equal(t, 5, got.Count)
+		assert.Contains(t, got.Vocabulary.Tags(), "goroutines")
+		assert.Equal(t, []string{
+			"content/a1/go/_index.md - invalid audience: ",
+			"content/a1/go/_index.md - outside importance is invalid",
+			"content/a1/go/_index.md - episode is missing for page: content/a1/go/20-bar.md",
+			"content/a1/go/10-foo.md - section is too short: summary (1 words, minimum 2)",
+			"content/a1/go/20-bar.md - file name is not prefixed with the weight of the page",
+			"content/a1/go/20-bar.md - file name does not match the dash joined weight and slug",
+			"content/a1/go/20-bar.md - duplicate weight in chapter: 10, also used by content/a1/go/10-foo.md",
+			"content/a1/go/20-bar.md - tag is an alias of `goroutines`: goroutine",
+			"content/a1/go/20-bar.md - section is too short: summary (1 words, minimum 2)",
+			"content/a1/rust/10-baz.md - main video problem [main-video-no-shortcode]: none of main-missing, main-really-missing, youtube, youtube-button found",
+			"content/a1/rust/10-baz.md - state mismatch. got: , want: stub",
+			"content/a1/rust/10-baz.md - summary section is missing",
+			"content/a1/rust/10-baz.md - topics section is missing",
+			"content/a1/rust/10-baz.md - invalid audience: ",
+			"content/a1/rust/10-baz.md - outside importance is invalid",
+		}, got.Errors())
+	})
+
+	t.Run("options replace the site files", func(t *testing.T) {
+		checker := NewChecker(
+			WithRoot("site"),
+			WithFS(fsys),
+			WithTagVocabulary(TagVocabulary{}),
+			WithSectionRules(map[string]SectionRule{}),
+			WithDocuments(map[string]string{
+				"site/content/a1/rust/10-baz.md": checkerTestPage,
+				"site/content/a1/rust/20-new.md": checkerTestPage,
+				"site/notes.md":                  "not a page",
+			}),
+		)
+
+		// execute
+		got, err := checker.Check()
+
+		// verify
+		require.NoError(t, err)
+		assert.Equal(t, 6, got.Count)
+		assert.True(t, got.Vocabulary.IsEmpty())
+
+		for _, issue := range got.Errors() {
+			assert.NotContains(t, issue, "goroutine")
+			assert.NotContains(t, issue, "section is too short")
+		}
+
+		assert.Contains(t, got.Errors(), "site/content/a1/rust/20-new.md - duplicate weight in chapter: 10, also used by site/content/a1/rust/10-baz.md")
+	})
+
+	t.Run("commit times", func(t *testing.T) {
+		translated := checkerTestFS()
+		delete(translated, "content/a1/go/30-qux.tmp.md")
+		translated["content/a1/go/10-foo.pl.md"] = &fstest.MapFile{Data: []byte(checkerTestPage)}
+
+		checker := NewChecker(WithFS(translated), WithCommitTimes(map[string]time.Time{
+			"content/a1/go/10-foo.md":    time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+			"content/a1/go/10-foo.pl.md": time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		}))
+
+		// execute
+		got, err := checker.Check()
+
+		// verify
+		require.NoError(t, err)
+		assert.Contains(t, got.Errors(), "content/a1/go/10-foo.pl.md - translation is older than its source: 2024-05-01, source changed: 2024-05-02")
+	})
+
+	t.Run("max errors", func(t *testing.T) {
+		checker := NewChecker(WithFS(fsys), WithMaxErrors(1))
+
+		// execute
+		got, err := checker.Check()
+
+		// verify
+		require.NoError(t, err)
+		assert.Equal(t, 3, got.Count)
+	})
+
+	t.Run("invalid markdown", func(t *testing.T) {
+		checker := NewChecker(WithFS(fsys), WithDocuments(map[string]string{"content/a1/go/10-foo.md": "no front matter"}))
+
+		// execute
+		_, err := checker.Check()
+
+		// verify
+		require.ErrorContains(t, err, "cannot parse markdown: content/a1/go/10-foo.md")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		invalid := checkerTestFS()
+		invalid["mdcheck.json"] = &fstest.MapFile{Data: []byte(`{`)}
+
+		checker := NewChecker(WithFS(invalid))
+
+		// execute
+		_, err := checker.Check()
+
+		// verify
+		require.ErrorContains(t, err, "cannot parse config: mdcheck.json")
+	})
+}
+
+func TestChecker_CheckDocuments(t *testing.T) {
+	checker := NewChecker(WithFS(checkerTestFS()), WithDocuments(map[string]string{"content/a1/rust/10-baz.md": checkerTestPage}))
+
+	// execute
+	got, err := checker.CheckDocuments([]string{"content/a1/go/20-bar.md", "./content/a1/rust/10-baz.md", "README.md", "../content/a1/go/10-foo.md"})
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.Count)
+	assert.Equal(t, []string{"README.md", "../content/a1/go/10-foo.md"}, got.Skipped)
+	assert.Equal(t, []string{
+		"content/a1/go/20-bar.md - file name is not prefixed with the weight of the page",
+		"content/a1/go/20-bar.md - file name does not match the dash joined weight and slug",
+		"content/a1/go/20-bar.md - tag is an alias of `goroutines`: goroutine",
+		"content/a1/go/20-bar.md - section is too short: summary (1 words, minimum 2)",
+		"content/a1/rust/10-baz.md - file name does not match the dash joined weight and slug",
+		"content/a1/rust/10-baz.md - section is too short: summary (1 words, minimum 2)",
+	}, got.Errors())
+}
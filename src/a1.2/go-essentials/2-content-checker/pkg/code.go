@@ -0,0 +1,137 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+const languageGo = "go"
+
+type CodeBlock struct {
+	Language string
+	// Line is the line number of the opening fence in the markdown file
+	Line   int
+	Code   string
+	Issues []string
+}
+
+type CodeBlocks []CodeBlock
+
+func (cb CodeBlocks) GetIssues() []string {
+	var issues []string
+
+	for _, block := range cb {
+		issues = append(issues, block.Issues...)
+	}
+
+	return issues
+}
+
+// extractCodeBlocks collects the fenced code blocks of a document, including the ones nested in lists
+func extractCodeBlocks(document *Document) CodeBlocks {
+	var blocks CodeBlocks
+
+	for _, node := range findNodes(document.Nodes, CodeBlockNode) {
+		if !node.Fenced {
+			continue
+		}
+
+		block := CodeBlock{Language: node.Language, Line: node.Start.Line, Code: node.Text}
+
+		if node.Unclosed {
+			block.Issues = append(block.Issues, fmt.Sprintf("line %d: code block is not closed", block.Line))
+		}
+
+		if block.Language == "" {
+			block.Issues = append(block.Issues, fmt.Sprintf("line %d: code block without a language tag", block.Line))
+		}
+
+		if block.Language == languageGo {
+			block.Issues = append(block.Issues, checkGoCode(block.Code, block.Line)...)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+// checkGoCode parses Go code, which can be a whole file, a list of declarations or a list of statements, and checks
+// if it is formatted. fenceLine is used to map the line numbers of the code back to the markdown file.
+func checkGoCode(code string, fenceLine int) []string {
+	if strings.TrimSpace(code) == "" {
+		return []string{fmt.Sprintf("line %d: empty go code block", fenceLine)}
+	}
+
+	if err := parseGoSnippet(code); err != nil {
+		var errList scanner.ErrorList
+		if errors.As(err, &errList) && len(errList) > 0 {
+			// errors found in the closing wrapper are reported on the last line of the code
+			line := min(errList[0].Pos.Line, strings.Count(code, EOL)+1)
+
+			return []string{fmt.Sprintf("line %d: go syntax error: %s", fenceLine+line, errList[0].Msg)}
+		}
+
+		return []string{fmt.Sprintf("line %d: go syntax error: %s", fenceLine, err)}
+	}
+
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		return []string{fmt.Sprintf("line %d: go code cannot be formatted: %s", fenceLine, err)}
+	}
+
+	got := strings.Split(strings.TrimRight(code, EOL), EOL)
+	want := strings.Split(strings.TrimRight(string(formatted), EOL), EOL)
+
+	for i := 0; i < len(got) || i < len(want); i++ {
+		if i < len(got) && i < len(want) && got[i] == want[i] {
+			continue
+		}
+
+		return []string{fmt.Sprintf("line %d: go code is not gofmt formatted", fenceLine+i+1)}
+	}
+
+	return nil
+}
+
+// parseGoSnippet parses the code as a file, then as declarations and finally as statements. Wrappers are added to the
+// first line, so that the line numbers of errors match the snippet.
+func parseGoSnippet(code string) error {
+	fset := token.NewFileSet()
+
+	_, fileErr := parser.ParseFile(fset, "", code, parser.AllErrors)
+	if fileErr == nil || strings.HasPrefix(strings.TrimSpace(code), "package ") {
+		return fileErr
+	}
+
+	_, declErr := parser.ParseFile(fset, "", "package main;"+code, parser.AllErrors)
+	if declErr == nil {
+		return nil
+	}
+
+	_, stmtErr := parser.ParseFile(fset, "", "package main; func _() {"+code+"\n}", parser.AllErrors)
+	if stmtErr == nil {
+		return nil
+	}
+
+	// the attempt which got further is the most likely to describe the real problem
+	if errorLine(stmtErr) >= errorLine(declErr) {
+		return stmtErr
+	}
+
+	return declErr
+}
+
+func errorLine(err error) int {
+	var errList scanner.ErrorList
+	if errors.As(err, &errList) && len(errList) > 0 {
+		return errList[0].Pos.Line
+	}
+
+	return 0
+}
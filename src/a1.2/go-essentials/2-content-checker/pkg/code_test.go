@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_extractCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    CodeBlocks
+	}{
+		{
+			name:    "no code blocks",
+			content: "+++\ntitle = \"Foo\"\n+++\n\nFoo\n",
+			want:    nil,
+		},
+		{
+			name:    "valid blocks",
+			content: "+++\ntitle = \"Foo\"\n+++\n\n```go\nfmt.Println(\"foo\")\n```\n\n~~~shell {linenos=true}\ngo run .\n~~~\n",
+			want: CodeBlocks{
+				{Language: "go", Line: 5, Code: "fmt.Println(\"foo\")"},
+				{Language: "shell", Line: 9, Code: "go run ."},
+			},
+		},
+		{
+			name:    "missing language, fence in code and not closed",
+			content: "+++\n+++\n```\nfoo\n```\n\n````md\n```\n````\n\n```text\nbar",
+			want: CodeBlocks{
+				{Line: 3, Code: "foo", Issues: []string{"line 3: code block without a language tag"}},
+				{Language: "md", Line: 7, Code: "```"},
+				{Language: "text", Line: 11, Code: "bar", Issues: []string{"line 11: code block is not closed"}},
+			},
+		},
+		{
+			name:    "front matter is skipped",
+			content: "+++\ntitle = \"```\"\n+++\n",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := extractCodeBlocks(ParseDocument(tt.content))
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_checkGoCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want []string
+	}{
+		{
+			name: "file",
+			code: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"foo\")\n}",
+			want: nil,
+		},
+		{
+			name: "declarations",
+			code: "type Foo struct {\n\tBar string\n}\n\nfunc (f Foo) Baz() {}",
+			want: nil,
+		},
+		{
+			name: "statements",
+			code: "x := 3\nfor i := 0; i < x; i++ {\n\tfmt.Println(i)\n}",
+			want: nil,
+		},
+		{
+			name: "empty",
+			code: "\n",
+			want: []string{"line 10: empty go code block"},
+		},
+		{
+			name: "syntax error in statements",
+			code: "x := 3\nif x > 2 {\n\tfmt.Println(x)\n",
+			want: []string{"line 14: go syntax error: expected ';', found 'EOF'"},
+		},
+		{
+			name: "syntax error in file",
+			code: "package main\n\nfunc main() {\n\tx := \n}",
+			want: []string{"line 15: go syntax error: expected operand, found '}'"},
+		},
+		{
+			name: "not formatted",
+			code: "x := 3\nif x>2 {\n    fmt.Println(x)\n}",
+			want: []string{"line 12: go code is not gofmt formatted"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := checkGoCode(tt.code, 10)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const ConfigFileName = "mdcheck.json"
+
+// Config is the content of the mdcheck.json file found in the root of the site
+//
+//	{
+//	  "sections": {
+//	    "summary": {"minWords": 20, "maxWords": 300, "requireList": true, "minReadability": 50},
+//	    "topics": {"minItems": 3, "maxItems": 10}
+//	  },
+//	  "languages": ["pl", "de"],
+//	  "maxReviewAges": {"critical": 90, "optional": 1000}
+//	}
+type Config struct {
+	// Sections contains the rules of the sections by their lowercase titles
+	Sections map[string]SectionRule `json:"sections,omitempty"`
+	// Languages contains the languages every page must be translated to, the languages of the translations found are
+	// used if empty
+	Languages []string `json:"languages,omitempty"`
+	// MaxReviewAges contains the number of days a page may go without a review by importance, DefaultMaxReviewAges is
+	// used for the missing importance levels
+	MaxReviewAges map[Importance]int `json:"maxReviewAges,omitempty"`
+}
+
+func ParseConfig(raw []byte) (Config, error) {
+	var config Config
+
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return Config{}, err
+	}
+
+	sections := make(map[string]SectionRule, len(config.Sections))
+	for title, rule := range config.Sections {
+		if rule.MaxWords > 0 && rule.MinWords > rule.MaxWords {
+			return Config{}, fmt.Errorf("minimum words are more than maximum words in section rule: %s", title)
+		}
+
+		if rule.MaxItems > 0 && rule.MinItems > rule.MaxItems {
+			return Config{}, fmt.Errorf("minimum items are more than maximum items in section rule: %s", title)
+		}
+
+		sections[strings.ToLower(title)] = rule
+	}
+
+	config.Sections = sections
+
+	for importance, days := range config.MaxReviewAges {
+		if importance.Level() < 0 {
+			return Config{}, fmt.Errorf("unknown importance in max review ages: %s", importance)
+		}
+
+		if days <= 0 {
+			return Config{}, fmt.Errorf("max review age is not positive: %s", importance)
+		}
+	}
+
+	return config, nil
+}
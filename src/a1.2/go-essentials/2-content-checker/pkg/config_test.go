@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			raw:  "{}",
+			want: Config{Sections: map[string]SectionRule{}},
+		},
+		{
+			name: "titles are lowercased",
+			raw:  `{"sections": {"Summary": {"minWords": 20, "requireList": true, "minReadability": 50}, "topics": {"minItems": 3, "maxItems": 10}}}`,
+			want: Config{Sections: map[string]SectionRule{
+				"summary": {MinWords: 20, RequireList: true, MinReadability: 50},
+				"topics":  {MinItems: 3, MaxItems: 10},
+			}},
+		},
+		{
+			name: "languages",
+			raw:  `{"languages": ["pl", "de"]}`,
+			want: Config{Sections: map[string]SectionRule{}, Languages: []string{"pl", "de"}},
+		},
+		{
+			name: "max review ages",
+			raw:  `{"maxReviewAges": {"critical": 90, "optional": 1000}}`,
+			want: Config{Sections: map[string]SectionRule{}, MaxReviewAges: map[Importance]int{Critical: 90, Optional: 1000}},
+		},
+		{
+			name:    "unknown importance in max review ages",
+			raw:     `{"maxReviewAges": {"urgent": 90}}`,
+			wantErr: true,
+		},
+		{
+			name:    "max review age not positive",
+			raw:     `{"maxReviewAges": {"critical": 0}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			raw:     `{"sections": [}`,
+			wantErr: true,
+		},
+		{
+			name:    "minimum words above maximum",
+			raw:     `{"sections": {"summary": {"minWords": 20, "maxWords": 10}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "minimum items above maximum",
+			raw:     `{"sections": {"topics": {"minItems": 5, "maxItems": 3}}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got, err := ParseConfig([]byte(tt.raw))
+
+			// verify
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
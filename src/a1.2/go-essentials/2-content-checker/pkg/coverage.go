@@ -0,0 +1,188 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OutsideAudience collects the pages by their outside importance, meaning their importance for people not belonging
+// to the target audience
+const OutsideAudience Audience = "outside"
+
+type CoverageRow struct {
+	Audience   Audience   `json:"audience"`
+	Importance Importance `json:"importance"`
+	Stub       int        `json:"stub"`
+	Incomplete int        `json:"incomplete"`
+	Complete   int        `json:"complete"`
+}
+
+func (cr CoverageRow) Total() int {
+	return cr.Stub + cr.Incomplete + cr.Complete
+}
+
+// HasCriticalStubs returns true if critical or essential pages are still stubs
+func (cr CoverageRow) HasCriticalStubs() bool {
+	return cr.Stub > 0 && cr.Importance.Level() >= Essential.Level()
+}
+
+type Coverage struct {
+	Rows          []CoverageRow `json:"rows"`
+	CriticalStubs []string      `json:"criticalStubs"`
+}
+
+// Coverage counts the non-index pages by audience, importance and state
+func (c Courses) Coverage() Coverage {
+	audiences := append(append([]Audience{}, Audiences...), OutsideAudience)
+
+	rows := make([]CoverageRow, 0, len(audiences)*len(Importances))
+	positions := make(map[Audience]map[Importance]int, len(audiences))
+
+	for _, audience := range audiences {
+		positions[audience] = make(map[Importance]int, len(Importances))
+
+		for _, importance := range Importances {
+			positions[audience][importance] = len(rows)
+			rows = append(rows, CoverageRow{Audience: audience, Importance: importance})
+		}
+	}
+
+	coverage := Coverage{CriticalStubs: []string{}}
+
+	count := func(audience Audience, importance Importance, state State) {
+		position, ok := positions[audience][importance]
+		if !ok {
+			return
+		}
+
+		switch state {
+		case Complete:
+			rows[position].Complete++
+		case Incomplete:
+			rows[position].Incomplete++
+		default:
+			rows[position].Stub++
+		}
+	}
+
+	for _, course := range c {
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				if page.IsIndex() {
+					continue
+				}
+
+				content := page.Content
+
+				count(content.Audience, content.Importance, content.State)
+
+				if content.Audience != All {
+					count(OutsideAudience, content.OutsideImportance, content.State)
+				}
+
+				if content.State != Complete && content.State != Incomplete && content.Importance.Level() >= Essential.Level() {
+					coverage.CriticalStubs = append(coverage.CriticalStubs, page.FilePath)
+				}
+			}
+		}
+	}
+
+	coverage.Rows = rows
+
+	return coverage
+}
+
+// String returns the coverage as a matrix of audiences and importance levels. Each cell contains the number of stub,
+// incomplete and complete pages, cells with critical or essential stubs are highlighted.
+func (cv Coverage) String() string {
+	const cellWidth = 11
+
+	titleWidth := len("Audience")
+	for _, row := range cv.Rows {
+		titleWidth = max(titleWidth, len(row.Audience))
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(column("Audience", titleWidth, cliBold))
+	for _, importance := range Importances {
+		sb.WriteString(" | " + column(importance, cellWidth, cliBold))
+	}
+	sb.WriteString(EOL)
+
+	sb.WriteString(strings.Repeat("-", titleWidth+1))
+	for range Importances {
+		sb.WriteString("+" + strings.Repeat("-", cellWidth+2))
+	}
+	sb.WriteString(EOL)
+
+	for i, row := range cv.Rows {
+		if i%len(Importances) == 0 {
+			sb.WriteString(column(row.Audience, titleWidth, cliReset))
+		}
+
+		color := cliReset
+		if row.HasCriticalStubs() {
+			color = cliRed
+		}
+
+		cell := "-"
+		if row.Total() > 0 {
+			cell = fmt.Sprintf("%d/%d/%d", row.Stub, row.Incomplete, row.Complete)
+		}
+
+		sb.WriteString(" | " + column(cell, cellWidth, color))
+
+		if i%len(Importances) == len(Importances)-1 {
+			sb.WriteString(EOL)
+		}
+	}
+
+	sb.WriteString(EOL + "Cells: stub/incomplete/complete" + EOL)
+
+	if len(cv.CriticalStubs) > 0 {
+		sb.WriteString(EOL + fmt.Sprint(cliRed, "Critical and essential stubs:", cliReset) + EOL)
+
+		for _, filePath := range cv.CriticalStubs {
+			sb.WriteString("  - " + filePath + EOL)
+		}
+	}
+
+	return sb.String()
+}
+
+func (cv Coverage) CSV() (string, error) {
+	buf := &bytes.Buffer{}
+
+	w := csv.NewWriter(buf)
+
+	records := [][]string{{"audience", "importance", "stub", "incomplete", "complete"}}
+	for _, row := range cv.Rows {
+		records = append(records, []string{
+			string(row.Audience),
+			string(row.Importance),
+			strconv.Itoa(row.Stub),
+			strconv.Itoa(row.Incomplete),
+			strconv.Itoa(row.Complete),
+		})
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (cv Coverage) JSON() (string, error) {
+	data, err := json.MarshalIndent(cv, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + EOL, nil
+}
@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findCoverageRow(t *testing.T, coverage Coverage, audience Audience, importance Importance) CoverageRow {
+	for _, row := range coverage.Rows {
+		if row.Audience == audience && row.Importance == importance {
+			return row
+		}
+	}
+
+	t.Fatalf("row not found: %s, %s", audience, importance)
+
+	return CoverageRow{}
+}
+
+func TestCourses_Coverage(t *testing.T) {
+	courses := Courses{
+		{
+			Title: "a1",
+			Chapters: Chapters{
+				{
+					Pages: Pages{
+						{FilePath: "_index.md", Content: Content{Audience: All, Importance: Critical, Body: &IndexBody{}}},
+						{FilePath: "10-foo.md", Content: Content{Audience: All, Importance: Critical, State: Stub, Body: DefaultBody{}}},
+						{FilePath: "20-bar.md", Content: Content{Audience: All, Importance: Critical, State: Complete, Body: DefaultBody{}}},
+						{FilePath: "30-baz.md", Content: Content{Audience: LinuxUsers, Importance: Essential, OutsideImportance: Relevant, State: Incomplete, Body: DefaultBody{}}},
+						{FilePath: "40-qux.md", Content: Content{Audience: LinuxUsers, Importance: Optional, OutsideImportance: Optional, Body: DefaultBody{}}},
+					},
+				},
+			},
+		},
+	}
+
+	// execute
+	got := courses.Coverage()
+
+	// verify
+	assert.Len(t, got.Rows, (len(Audiences)+1)*len(Importances))
+	assert.Equal(t, CoverageRow{Audience: All, Importance: Critical, Stub: 1, Complete: 1}, findCoverageRow(t, got, All, Critical))
+	assert.Equal(t, CoverageRow{Audience: LinuxUsers, Importance: Essential, Incomplete: 1}, findCoverageRow(t, got, LinuxUsers, Essential))
+	assert.Equal(t, CoverageRow{Audience: LinuxUsers, Importance: Optional, Stub: 1}, findCoverageRow(t, got, LinuxUsers, Optional))
+	assert.Equal(t, CoverageRow{Audience: OutsideAudience, Importance: Relevant, Incomplete: 1}, findCoverageRow(t, got, OutsideAudience, Relevant))
+	assert.Equal(t, CoverageRow{Audience: OutsideAudience, Importance: Optional, Stub: 1}, findCoverageRow(t, got, OutsideAudience, Optional))
+	assert.Equal(t, []string{"10-foo.md"}, got.CriticalStubs)
+	assert.True(t, findCoverageRow(t, got, All, Critical).HasCriticalStubs())
+	assert.False(t, findCoverageRow(t, got, LinuxUsers, Optional).HasCriticalStubs())
+}
+
+func TestCoverage_CSV(t *testing.T) {
+	coverage := Coverage{Rows: []CoverageRow{{Audience: LinuxUsers, Importance: Critical, Stub: 1, Incomplete: 2, Complete: 3}}}
+
+	// execute
+	got, err := coverage.CSV()
+	require.NoError(t, err)
+
+	// verify
+	assert.Equal(t, "audience,importance,stub,incomplete,complete\nLinux users,critical,1,2,3\n", got)
+}
+
+func TestCoverage_JSON(t *testing.T) {
+	coverage := Coverage{
+		Rows:          []CoverageRow{{Audience: All, Importance: Critical, Stub: 1}},
+		CriticalStubs: []string{"10-foo.md"},
+	}
+
+	// execute
+	got, err := coverage.JSON()
+	require.NoError(t, err)
+
+	// verify
+	assert.JSONEq(t, `{
+		"rows": [{"audience": "all", "importance": "critical", "stub": 1, "incomplete": 0, "complete": 0}],
+		"criticalStubs": ["10-foo.md"]
+	}`, got)
+}
@@ -0,0 +1,472 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PageChange string
+
+const (
+	PageAdded   PageChange = "added"
+	PageRemoved PageChange = "removed"
+	PageRenamed PageChange = "renamed"
+	PageChanged PageChange = "changed"
+)
+
+// PageDiff is a page which was added, removed, renamed or changed its state. File paths are relative to the roots of
+// the compared sites.
+type PageDiff struct {
+	Change PageChange
+	// OldFilePath is the path before the page was renamed or removed
+	OldFilePath string
+	// FilePath is the path after the page was added, renamed or changed
+	FilePath    string
+	Title       string
+	StateBefore State
+	StateAfter  State
+}
+
+func (pd PageDiff) StateChanged() bool {
+	return pd.Change == PageRenamed && pd.StateBefore != pd.StateAfter || pd.Change == PageChanged
+}
+
+type CourseDiff struct {
+	Title        string
+	Pages        []PageDiff
+	IssuesBefore int
+	IssuesAfter  int
+}
+
+// ByChange returns the page diffs of the given kind of change, renamed pages are also state changes if their states
+// differ
+func (cd CourseDiff) ByChange(change PageChange) []PageDiff {
+	var pages []PageDiff
+
+	for _, page := range cd.Pages {
+		if page.Change == change || change == PageChanged && page.StateChanged() {
+			pages = append(pages, page)
+		}
+	}
+
+	return pages
+}
+
+type ContentDiff []CourseDiff
+
+type diffPage struct {
+	filePath string
+	page     Page
+}
+
+// DiffCourses compares the pages of two versions of the site. Pages are matched by their paths relative to the roots
+// of the sites, removed and added pages of a course with the same slug are reported as renamed. Chapter index pages and
+// translations are not compared, but their issues are counted. Courses without any change are left out.
+func DiffCourses(before Courses, beforeRoot string, after Courses, afterRoot string) ContentDiff {
+	var titles []string
+
+	found := make(map[string]struct{})
+
+	for _, courses := range []Courses{after, before} {
+		for _, course := range courses {
+			if _, ok := found[course.Title]; !ok {
+				found[course.Title] = struct{}{}
+				titles = append(titles, course.Title)
+			}
+		}
+	}
+
+	var diff ContentDiff
+
+	for _, title := range titles {
+		courseDiff := CourseDiff{Title: title}
+
+		beforePages, issuesBefore := coursePages(before, beforeRoot, title)
+		afterPages, issuesAfter := coursePages(after, afterRoot, title)
+
+		courseDiff.IssuesBefore, courseDiff.IssuesAfter = issuesBefore, issuesAfter
+
+		var removed, added []diffPage
+
+		afterByPath := make(map[string]diffPage, len(afterPages))
+		for _, current := range afterPages {
+			afterByPath[current.filePath] = current
+		}
+
+		beforeByPath := make(map[string]diffPage, len(beforePages))
+		for _, old := range beforePages {
+			beforeByPath[old.filePath] = old
+		}
+
+		for _, old := range beforePages {
+			current, ok := afterByPath[old.filePath]
+			if !ok {
+				removed = append(removed, old)
+
+				continue
+			}
+
+			if old.page.GetState() != current.page.GetState() {
+				courseDiff.Pages = append(courseDiff.Pages, PageDiff{
+					Change:      PageChanged,
+					OldFilePath: old.filePath,
+					FilePath:    current.filePath,
+					Title:       current.page.Content.Title,
+					StateBefore: old.page.GetState(),
+					StateAfter:  current.page.GetState(),
+				})
+			}
+		}
+
+		for _, current := range afterPages {
+			if _, ok := beforeByPath[current.filePath]; !ok {
+				added = append(added, current)
+			}
+		}
+
+		for _, old := range removed {
+			idx := -1
+			if old.page.Content.Slug != "" {
+				idx = indexOfSlug(added, old.page.Content.Slug)
+			}
+
+			if idx < 0 {
+				courseDiff.Pages = append(courseDiff.Pages, PageDiff{
+					Change:      PageRemoved,
+					OldFilePath: old.filePath,
+					Title:       old.page.Content.Title,
+					StateBefore: old.page.GetState(),
+				})
+
+				continue
+			}
+
+			current := added[idx]
+			added = append(added[:idx], added[idx+1:]...)
+
+			courseDiff.Pages = append(courseDiff.Pages, PageDiff{
+				Change:      PageRenamed,
+				OldFilePath: old.filePath,
+				FilePath:    current.filePath,
+				Title:       current.page.Content.Title,
+				StateBefore: old.page.GetState(),
+				StateAfter:  current.page.GetState(),
+			})
+		}
+
+		for _, current := range added {
+			courseDiff.Pages = append(courseDiff.Pages, PageDiff{
+				Change:     PageAdded,
+				FilePath:   current.filePath,
+				Title:      current.page.Content.Title,
+				StateAfter: current.page.GetState(),
+			})
+		}
+
+		if len(courseDiff.Pages) > 0 || courseDiff.IssuesBefore != courseDiff.IssuesAfter {
+			diff = append(diff, courseDiff)
+		}
+	}
+
+	return diff
+}
+
+// coursePages returns the non-index source pages of the course with the given title and the number of its issues
+func coursePages(courses Courses, root, title string) ([]diffPage, int) {
+	for _, course := range courses {
+		if course.Title != title {
+			continue
+		}
+
+		var pages []diffPage
+
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				if page.IsIndex() {
+					continue
+				}
+
+				pages = append(pages, diffPage{filePath: relativePath(root, page.FilePath), page: page})
+			}
+		}
+
+		return pages, len(course.GetErrors())
+	}
+
+	return nil, 0
+}
+
+func relativePath(root, filePath string) string {
+	rel, err := filepath.Rel(root, filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+func indexOfSlug(pages []diffPage, slug string) int {
+	for i, page := range pages {
+		if page.page.Content.Slug == slug {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func issueDelta(before, after int) string {
+	return fmt.Sprintf("%d -> %d (%+d)", before, after, after-before)
+}
+
+func stateTransition(before, after State) string {
+	return fmt.Sprintf("%s -> %s", before, after)
+}
+
+func (cd ContentDiff) String() string {
+	var sb strings.Builder
+
+	for _, course := range cd {
+		sb.WriteString(fmt.Sprintf("%s%s%s - issues: %s\n", cliBold, course.Title, cliReset, issueDelta(course.IssuesBefore, course.IssuesAfter)))
+
+		for _, page := range course.Pages {
+			switch page.Change {
+			case PageAdded:
+				sb.WriteString(fmt.Sprintf("    %sadded%s   %s (%s)\n", cliGreen, cliReset, page.FilePath, page.StateAfter))
+			case PageRemoved:
+				sb.WriteString(fmt.Sprintf("    %sremoved%s %s (%s)\n", cliRed, cliReset, page.OldFilePath, page.StateBefore))
+			case PageRenamed:
+				sb.WriteString(fmt.Sprintf("    %srenamed%s %s -> %s", cliBlue, cliReset, page.OldFilePath, page.FilePath))
+
+				if page.StateChanged() {
+					sb.WriteString(", " + stateTransition(page.StateBefore, page.StateAfter))
+				}
+
+				sb.WriteString(EOL)
+			case PageChanged:
+				sb.WriteString(fmt.Sprintf("    %sstate%s   %s: %s\n", cliYellow, cliReset, page.FilePath, stateTransition(page.StateBefore, page.StateAfter)))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// Markdown returns the diff formatted for release notes
+func (cd ContentDiff) Markdown() string {
+	var sb strings.Builder
+
+	for i, course := range cd {
+		if i > 0 {
+			sb.WriteString(EOL)
+		}
+
+		sb.WriteString(fmt.Sprintf("## %s\n\nIssues: %s\n", course.Title, issueDelta(course.IssuesBefore, course.IssuesAfter)))
+
+		sections := []struct {
+			title  string
+			change PageChange
+			line   func(page PageDiff) string
+		}{
+			{"Added", PageAdded, func(page PageDiff) string {
+				return fmt.Sprintf("%s (`%s`), %s", page.Title, page.FilePath, page.StateAfter)
+			}},
+			{"Removed", PageRemoved, func(page PageDiff) string {
+				return fmt.Sprintf("%s (`%s`), %s", page.Title, page.OldFilePath, page.StateBefore)
+			}},
+			{"Renamed", PageRenamed, func(page PageDiff) string {
+				return fmt.Sprintf("%s: `%s` -> `%s`", page.Title, page.OldFilePath, page.FilePath)
+			}},
+			{"State changes", PageChanged, func(page PageDiff) string {
+				return fmt.Sprintf("%s (`%s`): %s", page.Title, page.FilePath, stateTransition(page.StateBefore, page.StateAfter))
+			}},
+		}
+
+		for _, section := range sections {
+			pages := course.ByChange(section.change)
+			if len(pages) == 0 {
+				continue
+			}
+
+			sb.WriteString(fmt.Sprintf("\n### %s\n\n", section.title))
+
+			for _, page := range pages {
+				sb.WriteString("- " + section.line(page) + EOL)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// GitFS returns the files of the site in root as of the given git revision, only the content directories, the tag
+// vocabulary and the config are read
+func GitFS(root, revision string) (fs.FS, error) {
+	return revisionFiles(root, revision)
+}
+
+func revisionFiles(root, revision string) (memoryFS, error) {
+	output, err := exec.Command("git", "-C", root, "ls-tree", "-r", "-z", revision).Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list files of revision %s in %s, err: %w", revision, root, err)
+	}
+
+	var filePaths, objects []string
+
+	for _, entry := range strings.Split(string(output), "\x00") {
+		// entries are formatted as `<mode> <type> <object>\t<file>`
+		meta, filePath, ok := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+
+		if !ok || len(fields) != 3 || fields[1] != "blob" || !isSiteFile(filePath) {
+			continue
+		}
+
+		filePaths = append(filePaths, filePath)
+		objects = append(objects, fields[2])
+	}
+
+	cmd := exec.Command("git", "-C", root, "cat-file", "--batch")
+	cmd.Stdin = strings.NewReader(strings.Join(objects, "\n") + "\n")
+
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read files of revision %s in %s, err: %w", revision, root, err)
+	}
+
+	return parseCatFileBatch(output, filePaths)
+}
+
+// isSiteFile returns true for the files of a content directory, the tag vocabulary and the config
+func isSiteFile(filePath string) bool {
+	dir, _, _ := strings.Cut(filePath, "/")
+
+	return isContentDir(dir) && dir != filePath || filePath == TagVocabularyFileName || filePath == ConfigFileName
+}
+
+// parseCatFileBatch parses the output of `git cat-file --batch`, the objects are expected in the order of the file paths
+func parseCatFileBatch(output []byte, filePaths []string) (memoryFS, error) {
+	fsys := make(memoryFS, len(filePaths))
+	reader := bufio.NewReader(bytes.NewReader(output))
+
+	for _, filePath := range filePaths {
+		// every object starts with a `<object> <type> <size>` line and ends with a new line
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("cannot read object of %s, err: %w", filePath, err)
+		}
+
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("cannot read object of %s: %s", filePath, strings.TrimSpace(header))
+		}
+
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid object size of %s: %s", filePath, fields[2])
+		}
+
+		data := make([]byte, size+1)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, fmt.Errorf("cannot read object of %s, err: %w", filePath, err)
+		}
+
+		fsys[filePath] = data[:size]
+	}
+
+	return fsys, nil
+}
+
+// memoryFS is a file system of files kept in memory keyed by their slash separated paths. Directories are implied by
+// the paths, they can be globbed but not opened.
+type memoryFS map[string][]byte
+
+func (m memoryFS) Open(name string) (fs.File, error) {
+	data, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &memoryFile{Reader: bytes.NewReader(data), info: memoryFileInfo{name: path.Base(name), size: int64(len(data))}}, nil
+}
+
+func (m memoryFS) ReadFile(name string) ([]byte, error) {
+	data, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return append([]byte{}, data...), nil
+}
+
+// Glob returns the sorted paths of the files matching the pattern, the wildcards do not match the separators of
+// directories
+func (m memoryFS) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	var matches []string
+
+	for name := range m {
+		if ok, _ := path.Match(pattern, name); ok {
+			matches = append(matches, name)
+		}
+	}
+
+	sort.Strings(matches)
+
+	return matches, nil
+}
+
+type memoryFile struct {
+	*bytes.Reader
+	info memoryFileInfo
+}
+
+func (mf *memoryFile) Stat() (fs.FileInfo, error) {
+	return mf.info, nil
+}
+
+func (mf *memoryFile) Close() error {
+	return nil
+}
+
+type memoryFileInfo struct {
+	name string
+	size int64
+}
+
+func (mfi memoryFileInfo) Name() string {
+	return mfi.name
+}
+
+func (mfi memoryFileInfo) Size() int64 {
+	return mfi.size
+}
+
+func (mfi memoryFileInfo) Mode() fs.FileMode {
+	return 0o444
+}
+
+func (mfi memoryFileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (mfi memoryFileInfo) IsDir() bool {
+	return false
+}
+
+func (mfi memoryFileInfo) Sys() any {
+	return nil
+}
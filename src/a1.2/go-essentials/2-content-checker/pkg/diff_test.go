@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCourses(t *testing.T) {
+	tests := []struct {
+		name   string
+		before Courses
+		after  Courses
+		want   ContentDiff
+	}{
+		{
+			name: "no changes",
+			before: testCourses(
+				Page{FilePath: "a/content/a1/go/_index.md", Content: Content{Body: &IndexBody{}}},
+				testPage("a/content/a1/go/10-foo.md", "foo", Stub),
+			),
+			after: testCourses(
+				Page{FilePath: "b/content/a1/go/_index.md", Content: Content{Body: &IndexBody{}}},
+				testPage("b/content/a1/go/10-foo.md", "foo", Stub),
+			),
+			want: nil,
+		},
+		{
+			name: "added, removed, renamed and changed",
+			before: testCourses(
+				Page{FilePath: "a/content/a1/go/_index.md", Content: Content{Body: &IndexBody{}}},
+				testPage("a/content/a1/go/10-foo.md", "foo", Stub),
+				testPage("a/content/a1/go/20-bar.md", "bar", Stub),
+				testPage("a/content/a1/go/30-baz.md", "baz", Incomplete),
+			),
+			after: testCourses(
+				Page{FilePath: "b/content/a1/go/_index.md", Content: Content{Body: &IndexBody{}}},
+				testPage("b/content/a1/go/10-foo.md", "foo", Incomplete),
+				testPage("b/content/a1/go/25-bar.md", "bar", Complete),
+				testPage("b/content/a1/go/40-qux.md", "qux", Stub),
+			),
+			want: ContentDiff{
+				{
+					Title: "a1",
+					Pages: []PageDiff{
+						{Change: PageChanged, OldFilePath: "content/a1/go/10-foo.md", FilePath: "content/a1/go/10-foo.md", Title: "foo", StateBefore: Stub, StateAfter: Incomplete},
+						{Change: PageRenamed, OldFilePath: "content/a1/go/20-bar.md", FilePath: "content/a1/go/25-bar.md", Title: "bar", StateBefore: Stub, StateAfter: Complete},
+						{Change: PageRemoved, OldFilePath: "content/a1/go/30-baz.md", Title: "baz", StateBefore: Incomplete},
+						{Change: PageAdded, FilePath: "content/a1/go/40-qux.md", Title: "qux", StateAfter: Stub},
+					},
+					IssuesBefore: 22,
+					IssuesAfter:  23,
+				},
+			},
+		},
+		{
+			name:   "new course",
+			before: Courses{},
+			after: testCourses(
+				Page{FilePath: "b/content/a1/go/_index.md", Content: Content{Body: &IndexBody{}}},
+				testPage("b/content/a1/go/10-foo.md", "foo", Stub),
+			),
+			want: ContentDiff{
+				{
+					Title: "a1",
+					Pages: []PageDiff{
+						{Change: PageAdded, FilePath: "content/a1/go/10-foo.md", Title: "foo", StateAfter: Stub},
+					},
+					IssuesBefore: 0,
+					IssuesAfter:  9,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := DiffCourses(tt.before, "a", tt.after, "b")
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContentDiff_Markdown(t *testing.T) {
+	diff := ContentDiff{
+		{
+			Title: "a1",
+			Pages: []PageDiff{
+				{Change: PageRenamed, OldFilePath: "content/a1/go/20-bar.md", FilePath: "content/a1/go/25-bar.md", Title: "Bar", StateBefore: Stub, StateAfter: Complete},
+				{Change: PageAdded, FilePath: "content/a1/go/40-qux.md", Title: "Qux", StateAfter: Stub},
+			},
+			IssuesBefore: 5,
+			IssuesAfter:  3,
+		},
+		{
+			Title:        "b1",
+			IssuesBefore: 1,
+			IssuesAfter:  2,
+		},
+	}
+
+	// execute
+	got := diff.Markdown()
+
+	// verify
+	assert.Equal(t, "## a1\n\nIssues: 5 -> 3 (-2)\n\n### Added\n\n- Qux (`content/a1/go/40-qux.md`), stub\n\n### Renamed\n\n- Bar: `content/a1/go/20-bar.md` -> `content/a1/go/25-bar.md`\n\n### State changes\n\n- Bar (`content/a1/go/25-bar.md`): stub -> complete\n\n## b1\n\nIssues: 1 -> 2 (+1)\n", got)
+}
+
+func Test_parseCatFileBatch(t *testing.T) {
+	output := "1234 blob 5\nfoo\n\n\n5678 blob 0\n\n"
+
+	// execute
+	got, err := parseCatFileBatch([]byte(output), []string{"content/a1/_index.md", "tags.txt"})
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "foo\n\n", string(got["content/a1/_index.md"]))
+	assert.Equal(t, "", string(got["tags.txt"]))
+
+	_, err = parseCatFileBatch([]byte("1234 missing\n"), []string{"content/a1/_index.md"})
+	require.ErrorContains(t, err, "cannot read object of content/a1/_index.md: 1234 missing")
+}
+
+func Test_isSiteFile(t *testing.T) {
+	assert.True(t, isSiteFile("content/a1/go/10-foo.md"))
+	assert.True(t, isSiteFile("content.pl/a1/go/10-foo.md"))
+	assert.True(t, isSiteFile(TagVocabularyFileName))
+	assert.False(t, isSiteFile("content"))
+	assert.False(t, isSiteFile("static/content/foo.md"))
+}
+
+func Test_memoryFS(t *testing.T) {
+	fsys := memoryFS{
+		"content/a1/_index.md":    []byte("a1"),
+		"content/a1/go/10-foo.md": []byte("foo"),
+		"content.pl/a1/_index.md": []byte("pl"),
+		"tags.txt":                []byte("go"),
+	}
+
+	t.Run("glob", func(t *testing.T) {
+		// execute
+		got, err := fs.Glob(fsys, "content*/*/_index*.md")
+
+		// verify
+		require.NoError(t, err)
+		assert.Equal(t, []string{"content.pl/a1/_index.md", "content/a1/_index.md"}, got)
+	})
+
+	t.Run("read", func(t *testing.T) {
+		// execute
+		got, err := fs.ReadFile(fsys, "content/a1/go/10-foo.md")
+
+		// verify
+		require.NoError(t, err)
+		assert.Equal(t, "foo", string(got))
+
+		_, err = fsys.Open("content/a1")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("stat", func(t *testing.T) {
+		// execute
+		got, err := fs.Stat(fsys, "tags.txt")
+
+		// verify
+		require.NoError(t, err)
+		assert.Equal(t, "tags.txt", got.Name())
+		assert.Equal(t, int64(2), got.Size())
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -40,6 +42,9 @@ const (
 	Audio       Badge = "audio"
 )
 
+// Badges lists the badges known by the related videos sections
+var Badges = []Badge{Alternative, Extra, Fun, Hint, MustSee, Summary, Unchecked, NoEmbed, Audio}
+
 type Audience string
 
 const (
@@ -70,6 +75,21 @@ var validAudiences = map[Audience]struct{}{
 	SysAdmins:         {},
 }
 
+// Audiences lists the valid audiences, starting with the broadest ones
+var Audiences = []Audience{
+	All,
+	AllProfessionals,
+	AllDevelopers,
+	LinuxUsers,
+	WindowsUsers,
+	MacUsers,
+	WebDevelopers,
+	MobileDevelopers,
+	DesktopDevelopers,
+	GameDevelopers,
+	SysAdmins,
+}
+
 type Importance string
 
 const (
@@ -80,6 +100,9 @@ const (
 	Optional  Importance = "optional"
 )
 
+// Importances lists the valid importance levels, starting with the highest
+var Importances = []Importance{Critical, Essential, Important, Relevant, Optional}
+
 func (i Importance) Level() int {
 	switch i {
 	case Critical:
@@ -136,7 +159,9 @@ func (rv RelatedVideos) Has(badge Badge) bool {
 }
 
 type DefaultBody struct {
-	MainVideo          MainVideo
+	MainVideo MainVideo
+	// MainVideoProblem explains why the main video is a problem, it is nil otherwise
+	MainVideoProblem   *MainVideoProblem
 	HasSummary         bool
 	HasTopics          bool
 	HasExercises       bool
@@ -196,6 +221,11 @@ func (db DefaultBody) GetIssues(state State) []string {
 	issues := db.RelatedVideos.GetIssues()
 
 	switch db.MainVideo {
+	case VideoProblem:
+		// stubs are not expected to have a main video section yet
+		if db.MainVideoProblem != nil && (db.MainVideoProblem.Code != MainVideoNoShortcode || state != Stub) {
+			issues = append(issues, db.MainVideoProblem.Issue())
+		}
 	case VideoReallyMissing:
 		if db.UsefulWithoutVideo {
 			issues = append(issues, "main video is NOT REALLY missing (Remove the useful-without-video tag?")
@@ -251,13 +281,54 @@ func (db DefaultBody) IsSlugForced() bool {
 	return db.SlugForced
 }
 
+type Episode struct {
+	Title string
+	Link  string
+}
+
+var regexRef = regexp.MustCompile(`{{<\s*(?:rel)?ref\s+"([^"]*)"\s*>}}`)
+
+// Target returns the slug or file name the episode links to
+func (e Episode) Target() string {
+	link := e.Link
+
+	if matches := regexRef.FindStringSubmatch(link); len(matches) == 2 {
+		link = matches[1]
+	}
+
+	if idx := strings.Index(link, "#"); idx >= 0 {
+		link = link[:idx]
+	}
+
+	return strings.TrimSuffix(filepath.Base(strings.TrimRight(link, "/")), ".md")
+}
+
 type IndexBody struct {
 	HasEpisodes   bool
+	Episodes      []Episode
 	CompleteState State
 }
 
 func (ib *IndexBody) GetIssues(_ State) []string {
-	return nil
+	var issues []string
+
+	found := make(map[string]struct{}, len(ib.Episodes))
+
+	for _, episode := range ib.Episodes {
+		if episode.Link == "" {
+			issues = append(issues, "episode without a link: "+episode.Title)
+
+			continue
+		}
+
+		if _, exists := found[episode.Target()]; exists {
+			issues = append(issues, "duplicate episode: "+episode.Link)
+		}
+
+		found[episode.Target()] = struct{}{}
+	}
+
+	return issues
 }
 
 func (ib *IndexBody) CalculateState() State {
@@ -276,14 +347,72 @@ func (ib *IndexBody) IsSlugForced() bool {
 	return false
 }
 
+type Difficulty string
+
+const (
+	Easy   Difficulty = "easy"
+	Medium Difficulty = "medium"
+	Hard   Difficulty = "hard"
+)
+
+type Challenge struct {
+	Title      string
+	Link       string
+	Difficulty Difficulty
+	Issues     []string
+}
+
+type Challenges []Challenge
+
+func (c Challenges) GetIssues() []string {
+	var issues []string
+
+	for _, challenge := range c {
+		issues = append(issues, challenge.Issues...)
+	}
+
+	return issues
+}
+
 type PracticeBody struct {
 	HasDescription           bool
 	HasRecommendedChallenges bool
 	HasAdditionalChallenges  bool
+	RecommendedChallenges    Challenges
+	AdditionalChallenges     Challenges
+	SectionTitles            []string
 }
 
-func (pb PracticeBody) GetIssues(_ State) []string {
-	return nil
+var practiceBodySectionMap = map[string]int{
+	sectionRoot:                  0,
+	sectionDescription:           1,
+	sectionRecommendedChallenges: 2,
+	sectionAdditionalChallenges:  3,
+}
+
+func (pb PracticeBody) GetIssues(state State) []string {
+	var issues []string
+
+	if state != pb.CalculateState() {
+		issues = append(issues, fmt.Sprintf("state mismatch. got: %s, want: %s", state, pb.CalculateState()))
+	}
+
+	if item, ok := isOrderedCorrectly(practiceBodySectionMap, pb.SectionTitles); !ok {
+		issues = append(issues, "sections are not in the correct order, first out of order: "+item)
+	}
+
+	if pb.HasRecommendedChallenges && len(pb.RecommendedChallenges) == 0 {
+		issues = append(issues, "no challenges found in the recommended challenges section")
+	}
+
+	if pb.HasAdditionalChallenges && len(pb.AdditionalChallenges) == 0 {
+		issues = append(issues, "no challenges found in the additional challenges section")
+	}
+
+	issues = append(issues, pb.RecommendedChallenges.GetIssues()...)
+	issues = append(issues, pb.AdditionalChallenges.GetIssues()...)
+
+	return issues
 }
 
 func (pb PracticeBody) CalculateState() State {
@@ -310,6 +439,7 @@ type Body interface {
 
 type Content struct {
 	Title             string
+	Description       string
 	State             State
 	Body              Body
 	Slug              string
@@ -318,6 +448,11 @@ type Content struct {
 	Importance        Importance
 	OutsideImportance Importance
 	Tags              []string
+	Prerequisites     []string
+	CodeBlocks        CodeBlocks
+	SectionStats      SectionStats
+	// LastReviewed is the date the page was last reviewed in YYYY-MM-DD format, see ReviewDate
+	LastReviewed string
 }
 
 var regexDashes = regexp.MustCompile(`-+-`)
@@ -335,19 +470,48 @@ func slugify(title string) string {
 	return strings.Trim(title, "-")
 }
 
+// WeightNumber returns the weight of the page as a number, false is returned if the weight is missing or invalid
+func (c Content) WeightNumber() (int, bool) {
+	weight, err := strconv.Atoi(c.Weight)
+	if err != nil {
+		return 0, false
+	}
+
+	return weight, true
+}
+
 func (c Content) GetIssues(filePath string) []string {
-	issues := c.Body.GetIssues(c.State)
+	var issues []string
+
+	filename, _ := SplitLanguage(filepath.Base(filePath))
+	language := Language(filePath)
+
+	// the section titles of translations are localized, their structure is checked on the source pages
+	if language == "" {
+		issues = c.Body.GetIssues(c.State)
+	}
 
 	_, isIndex := c.Body.(*IndexBody)
+	if language != "" && filename == indexFileName {
+		isIndex = true
+	}
+
+	if _, ok := c.WeightNumber(); !ok {
+		if c.Weight != "" {
+			issues = append(issues, "weight is not a number: "+c.Weight)
+		} else if !isIndex {
+			issues = append(issues, "weight is missing")
+		}
+	}
+
 	if !isIndex {
-		filename := filepath.Base(filePath)
 		if !strings.HasPrefix(filename, c.Weight) {
 			issues = append(issues, "file name is not prefixed with the weight of the page")
 		}
 		if fmt.Sprintf("%s-%s.md", c.Weight, c.Slug) != filename {
 			issues = append(issues, "file name does not match the dash joined weight and slug")
 		}
-		if !c.Body.IsSlugForced() && c.Slug != slugify(c.Title) {
+		if language == "" && !c.Body.IsSlugForced() && c.Slug != slugify(c.Title) {
 			issues = append(issues, fmt.Sprintf("slug does not match the lowercase title with dashes (`%s`, `%s`)", c.Slug, slugify(c.Title)))
 		}
 	}
@@ -356,6 +520,10 @@ func (c Content) GetIssues(filePath string) []string {
 		issues = append(issues, "invalid audience: "+string(c.Audience))
 	}
 
+	if _, err := c.ReviewDate(); err != nil {
+		issues = append(issues, "last reviewed date is invalid: "+c.LastReviewed)
+	}
+
 	if c.Importance.Level() < c.OutsideImportance.Level() {
 		issues = append(issues, "importance is lower than outside importance")
 	}
@@ -380,6 +548,8 @@ func (c Content) GetIssues(filePath string) []string {
 		}
 	}
 
+	issues = append(issues, c.CodeBlocks.GetIssues()...)
+
 	return issues
 }
 
@@ -387,12 +557,29 @@ type Page struct {
 	FilePath string
 	Title    string
 	Content  Content
+	// Issues contains the problems which can only be detected knowing the other pages (e.g. duplicate weights)
+	Issues []string
+	// Language is the language code of translated pages, empty for pages in the default language
+	Language string
+	// Translations contains the translated versions of the page
+	Translations Pages
 }
 
 func (p Page) GetIssues() []string {
 	issues := p.Content.GetIssues(p.FilePath)
 
-	return issues
+	return append(issues, p.Issues...)
+}
+
+// IsIndex returns true for chapter index pages, including the ones without an episodes section
+func (p Page) IsIndex() bool {
+	if p.sourceFileName() == indexFileName {
+		return true
+	}
+
+	_, ok := p.Content.Body.(*IndexBody)
+
+	return ok
 }
 
 func (p Page) GetErrors() []string {
@@ -409,17 +596,19 @@ func (p Page) GetState() State {
 	return p.Content.State
 }
 
-func (p Page) String() string {
-	color := cliBlue
-
-	switch p.GetState() {
+func stateColor(state State) Color {
+	switch state {
 	case Complete:
-		color = cliGreen
+		return cliGreen
 	case Incomplete:
-		color = cliYellow
+		return cliYellow
 	default:
-		color = cliRed
+		return cliRed
 	}
+}
+
+func (p Page) String() string {
+	color := stateColor(p.GetState())
 
 	issues := p.GetIssues()
 	if len(issues) > 0 {
@@ -438,7 +627,7 @@ func (p Page) String() string {
 type Pages []Page
 
 func (p Pages) Add(filePath, pageFN string, content Content) Pages {
-	return append(p, Page{FilePath: filePath, Title: pageFN, Content: content})
+	return append(p, newPage(filePath, pageFN, content))
 }
 
 type Chapter struct {
@@ -454,6 +643,11 @@ func (c *Chapter) Prepare() {
 
 	c.prepared = true
 
+	c.groupTranslations()
+	c.sortPages()
+	c.checkWeights()
+	c.checkEpisodes()
+
 	var (
 		indexPage  *IndexBody
 		pagesExist = false
@@ -461,9 +655,10 @@ func (c *Chapter) Prepare() {
 	)
 
 	for _, page := range c.Pages {
-		chapter, ok := page.Content.Body.(*IndexBody)
-		if ok {
-			indexPage = chapter
+		if page.IsIndex() {
+			if chapter, ok := page.Content.Body.(*IndexBody); ok {
+				indexPage = chapter
+			}
 
 			continue
 		}
@@ -485,6 +680,172 @@ func (c *Chapter) Prepare() {
 	indexPage.SetCompleteState(Complete)
 }
 
+// sortPages orders the pages of the chapter by weight, keeping the index page first and the pages without a valid
+// weight last
+func (c *Chapter) sortPages() {
+	sort.SliceStable(c.Pages, func(i, j int) bool {
+		if c.Pages[i].IsIndex() != c.Pages[j].IsIndex() {
+			return c.Pages[i].IsIndex()
+		}
+
+		return lessWeight(c.Pages[i].Content, c.Pages[j].Content)
+	})
+}
+
+func lessWeight(a, b Content) bool {
+	weightA, okA := a.WeightNumber()
+	weightB, okB := b.WeightNumber()
+
+	if okA != okB {
+		return okA
+	}
+
+	return weightA < weightB
+}
+
+func (c *Chapter) checkWeights() {
+	used := make(map[int]string, len(c.Pages))
+
+	for i, page := range c.Pages {
+		if page.IsIndex() {
+			continue
+		}
+
+		// missing and invalid weights are reported by the page itself
+		weight, ok := page.Content.WeightNumber()
+		if !ok {
+			continue
+		}
+
+		if filePath, exists := used[weight]; exists {
+			c.Pages[i].Issues = append(c.Pages[i].Issues, fmt.Sprintf("duplicate weight in chapter: %d, also used by %s", weight, filePath))
+
+			continue
+		}
+
+		used[weight] = page.FilePath
+	}
+}
+
+// checkEpisodes compares the episodes listed on the index page with the pages of the chapter
+func (c *Chapter) checkEpisodes() {
+	index := c.GetIndex()
+	if index == nil {
+		return
+	}
+
+	body, ok := index.Content.Body.(*IndexBody)
+	if !ok || !body.HasEpisodes {
+		return
+	}
+
+	linked := make(map[int]struct{}, len(c.Pages))
+	lastPosition := -1
+	orderReported := false
+
+	for _, episode := range body.Episodes {
+		if episode.Link == "" {
+			continue
+		}
+
+		position := c.findPage(episode.Target())
+		if position < 0 {
+			index.Issues = append(index.Issues, "episode links to a non-existent page: "+episode.Link)
+
+			continue
+		}
+
+		linked[position] = struct{}{}
+
+		if position < lastPosition && !orderReported {
+			index.Issues = append(index.Issues, "episodes are not in the order of the page weights, first out of order: "+episode.Link)
+			orderReported = true
+		}
+
+		lastPosition = position
+	}
+
+	for i, page := range c.Pages {
+		if page.IsIndex() {
+			continue
+		}
+
+		if _, ok := linked[i]; !ok {
+			index.Issues = append(index.Issues, "episode is missing for page: "+page.FilePath)
+		}
+	}
+}
+
+// findPage returns the position of the non-index page with the given slug or file name, -1 if none is found
+func (c *Chapter) findPage(target string) int {
+	for i, page := range c.Pages {
+		if page.IsIndex() {
+			continue
+		}
+
+		if page.Content.Slug == target || strings.TrimSuffix(filepath.Base(page.FilePath), ".md") == target {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// GetIndex returns the index page of the chapter, nil is returned if the chapter has no index page
+func (c *Chapter) GetIndex() *Page {
+	for i, page := range c.Pages {
+		if page.IsIndex() {
+			return &c.Pages[i]
+		}
+	}
+
+	return nil
+}
+
+// CalculateState returns the state of the chapter based on the states of its non-index pages
+func (c *Chapter) CalculateState() State {
+	states := make([]State, 0, len(c.Pages))
+
+	for _, page := range c.Pages {
+		if page.IsIndex() {
+			continue
+		}
+
+		states = append(states, page.GetState())
+	}
+
+	return combineStates(states)
+}
+
+// combineStates returns complete if all states are complete, stub if none of them is complete or incomplete, and
+// incomplete otherwise
+func combineStates(states []State) State {
+	if len(states) == 0 {
+		return Stub
+	}
+
+	allComplete, allStub := true, true
+
+	for _, state := range states {
+		if state != Complete {
+			allComplete = false
+		}
+
+		if state == Complete || state == Incomplete {
+			allStub = false
+		}
+	}
+
+	switch {
+	case allComplete:
+		return Complete
+	case allStub:
+		return Stub
+	}
+
+	return Incomplete
+}
+
 func (c *Chapter) String(statesAllowed map[State]struct{}, printIndex, printNonIndex bool) string {
 	result := fmt.Sprintln("  ", c.Title)
 
@@ -506,6 +867,10 @@ func (c *Chapter) String(statesAllowed map[State]struct{}, printIndex, printNonI
 		}
 
 		result += page.String()
+
+		for _, translation := range page.Translations {
+			result += translation.String()
+		}
 	}
 
 	return result
@@ -516,6 +881,10 @@ func (c *Chapter) GetErrors() []string {
 
 	for _, page := range c.Pages {
 		errors = append(errors, page.GetErrors()...)
+
+		for _, translation := range page.Translations {
+			errors = append(errors, translation.GetErrors()...)
+		}
 	}
 
 	return errors
@@ -531,22 +900,128 @@ func (c Chapters) Add(filePath, chapterFN, pageFN string, content Content) Chapt
 		}
 	}
 
-	return append(c, &Chapter{Title: chapterFN, Pages: Pages{{FilePath: filePath, Title: pageFN, Content: content}}})
+	return append(c, &Chapter{Title: chapterFN, Pages: Pages{newPage(filePath, pageFN, content)}})
 }
 
 type Course struct {
-	Title    string
-	Chapters Chapters
+	Title string
+	Index *Page
+	// Translations contains the translated versions of the course index page
+	Translations Pages
+	Chapters     Chapters
+	prepared     bool
 }
 
-func (c Course) Prepare() {
+func (c *Course) Prepare() {
+	if c.prepared {
+		return
+	}
+
+	c.prepared = true
+
 	for _, chapter := range c.Chapters {
 		chapter.Prepare()
 	}
+
+	c.sortChapters()
+	c.checkChapterWeights()
+}
+
+// sortChapters orders the chapters by the weight of their index pages, chapters without one are moved to the end
+func (c *Course) sortChapters() {
+	sort.SliceStable(c.Chapters, func(i, j int) bool {
+		indexA, indexB := c.Chapters[i].GetIndex(), c.Chapters[j].GetIndex()
+		if indexA == nil || indexB == nil {
+			return indexA != nil
+		}
+
+		return lessWeight(indexA.Content, indexB.Content)
+	})
+}
+
+func (c *Course) checkChapterWeights() {
+	used := make(map[int]string, len(c.Chapters))
+
+	for _, chapter := range c.Chapters {
+		index := chapter.GetIndex()
+		if index == nil {
+			continue
+		}
+
+		weight, ok := index.Content.WeightNumber()
+		if !ok {
+			continue
+		}
+
+		if filePath, exists := used[weight]; exists {
+			index.Issues = append(index.Issues, fmt.Sprintf("duplicate weight in course: %d, also used by %s", weight, filePath))
+
+			continue
+		}
+
+		used[weight] = index.FilePath
+	}
+}
+
+// CalculateState returns the state of the course based on the states of its chapters
+func (c Course) CalculateState() State {
+	states := make([]State, 0, len(c.Chapters))
+
+	for _, chapter := range c.Chapters {
+		states = append(states, chapter.CalculateState())
+	}
+
+	return combineStates(states)
+}
+
+// GetIssues returns the problems found with the course index page
+func (c Course) GetIssues() []string {
+	if c.Index == nil {
+		return []string{"course index page is missing"}
+	}
+
+	issues := courseIndexIssues(c.Index.Content)
+
+	if content := c.Index.Content; content.State != "" && content.State != c.CalculateState() {
+		issues = append(issues, fmt.Sprintf("state mismatch. got: %s, want: %s", content.State, c.CalculateState()))
+	}
+
+	return append(issues, c.Index.Issues...)
+}
+
+// courseIndexIssues returns the problems of a course index page which can be found without knowing the course
+func courseIndexIssues(content Content) []string {
+	var issues []string
+
+	if content.Title == "" {
+		issues = append(issues, "course title is missing")
+	}
+
+	if content.Description == "" {
+		issues = append(issues, "course description is missing")
+	}
+
+	if _, exists := validAudiences[content.Audience]; !exists {
+		issues = append(issues, "invalid audience: "+string(content.Audience))
+	}
+
+	return issues
 }
 
 func (c Course) String(statesAllowed map[State]struct{}, printIndex, printNonIndex bool) string {
-	result := fmt.Sprintln(c.Title)
+	state := c.CalculateState()
+	issues := c.GetIssues()
+
+	color := stateColor(state)
+	if len(issues) > 0 {
+		color = cliRed
+	}
+
+	result := fmt.Sprintln(color, c.Title, "-", state, cliReset)
+
+	for _, issue := range issues {
+		result += fmt.Sprintln("    - ", issue)
+	}
 
 	for _, chapter := range c.Chapters {
 		result += chapter.String(statesAllowed, printIndex, printNonIndex)
@@ -558,6 +1033,40 @@ func (c Course) String(statesAllowed map[State]struct{}, printIndex, printNonInd
 func (c Course) GetErrors() []string {
 	var issues []string
 
+	filePath := c.Title
+	if c.Index != nil {
+		filePath = c.Index.FilePath
+	}
+
+	for _, issue := range c.GetIssues() {
+		issues = append(issues, fmt.Sprintf("%s - %s", filePath, issue))
+	}
+
+	// course index pages are validated by their courses, only the translation issues apply to their translations
+	for _, translation := range c.Translations {
+		for _, issue := range translation.Issues {
+			issues = append(issues, fmt.Sprintf("%s - %s", translation.FilePath, issue))
+		}
+	}
+
+	for _, chapter := range c.Chapters {
+		issues = append(issues, chapter.GetErrors()...)
+	}
+
+	return issues
+}
+
+// GetDocumentErrors returns the errors which are found by checking the pages one by one, the checks which need the
+// whole chapter or course are skipped. The course is expected not to be prepared.
+func (c Course) GetDocumentErrors() []string {
+	var issues []string
+
+	if c.Index != nil {
+		for _, issue := range append(courseIndexIssues(c.Index.Content), c.Index.Issues...) {
+			issues = append(issues, fmt.Sprintf("%s - %s", c.Index.FilePath, issue))
+		}
+	}
+
 	for _, chapter := range c.Chapters {
 		issues = append(issues, chapter.GetErrors()...)
 	}
@@ -594,6 +1103,31 @@ func (c Course) Stats() (int, int, int, int, int) {
 
 type Courses []Course
 
+// AddIndex sets the index page of a course or adds a translation of it, creating the course if necessary
+func (c Courses) AddIndex(filePath, courseFN string, content Content) Courses {
+	index := newPage(filePath, filepath.Base(filePath), content)
+
+	i := len(c)
+	for j, course := range c {
+		if course.Title == courseFN {
+			i = j
+			break
+		}
+	}
+
+	if i == len(c) {
+		c = append(c, Course{Title: courseFN})
+	}
+
+	if index.Language != "" {
+		c[i].Translations = append(c[i].Translations, index)
+	} else {
+		c[i].Index = &index
+	}
+
+	return c
+}
+
 func (c Courses) Add(filePath, courseFN, chapterFN, pageFN string, content Content) Courses {
 	for i, course := range c {
 		if course.Title == courseFN {
@@ -602,11 +1136,12 @@ func (c Courses) Add(filePath, courseFN, chapterFN, pageFN string, content Conte
 		}
 	}
 
-	return append(c, Course{Title: courseFN, Chapters: Chapters{{Title: chapterFN, Pages: Pages{{FilePath: filePath, Title: pageFN, Content: content}}}}})
+	return append(c, Course{Title: courseFN, Chapters: Chapters{{Title: chapterFN, Pages: Pages{newPage(filePath, pageFN, content)}}}})
 }
 
 type CourseStat struct {
 	Title      string
+	State      State
 	Total      int
 	Stub       int
 	Incomplete int
@@ -614,33 +1149,35 @@ type CourseStat struct {
 	Errors     int
 }
 
-func (cs *CourseStat) Print(columnWidths [7]int, columnColors [7]Color, total int) {
+func (cs *CourseStat) Print(columnWidths [8]int, columnColors [8]Color, total int) {
 	fmt.Printf(
-		"%s | %s | %s | %s | %s | %s | %s\n",
+		"%s | %s | %s | %s | %s | %s | %s | %s\n",
 		column(cs.Title, columnWidths[0], columnColors[0]),
-		column(cs.Total, columnWidths[1], columnColors[1]),
-		column(cs.Stub, columnWidths[2], columnColors[2]),
-		column(cs.Incomplete, columnWidths[3], columnColors[3]),
-		column(cs.Complete, columnWidths[4], columnColors[4]),
-		column(cs.Errors, columnWidths[5], columnColors[5]),
-		column(cs.Total*100/total, columnWidths[6], columnColors[6]),
+		column(cs.State, columnWidths[1], stateColor(cs.State)),
+		column(cs.Total, columnWidths[2], columnColors[2]),
+		column(cs.Stub, columnWidths[3], columnColors[3]),
+		column(cs.Incomplete, columnWidths[4], columnColors[4]),
+		column(cs.Complete, columnWidths[5], columnColors[5]),
+		column(cs.Errors, columnWidths[6], columnColors[6]),
+		column(cs.Total*100/total, columnWidths[7], columnColors[7]),
 	)
 }
 
-func (cs *CourseStat) PrintHead(columnWidths [7]int, columnColors [7]Color) {
+func (cs *CourseStat) PrintHead(columnWidths [8]int, columnColors [8]Color) {
 	fmt.Printf(
-		"%s | %s | %s | %s | %s | %s | %s\n",
+		"%s | %s | %s | %s | %s | %s | %s | %s\n",
 		column("Course", columnWidths[0], columnColors[0]),
-		column("All", columnWidths[1], columnColors[1]),
-		column("Stub", columnWidths[2], columnColors[2]),
-		column("Incomplete", columnWidths[3], columnColors[3]),
-		column("Complete", columnWidths[4], columnColors[4]),
-		column("Errors", columnWidths[5], columnColors[5]),
-		column("Percent", columnWidths[6], columnColors[6]),
+		column("State", columnWidths[1], columnColors[1]),
+		column("All", columnWidths[2], columnColors[2]),
+		column("Stub", columnWidths[3], columnColors[3]),
+		column("Incomplete", columnWidths[4], columnColors[4]),
+		column("Complete", columnWidths[5], columnColors[5]),
+		column("Errors", columnWidths[6], columnColors[6]),
+		column("Percent", columnWidths[7], columnColors[7]),
 	)
 }
 
-func (cs *CourseStat) Line(columnWidths [7]int) {
+func (cs *CourseStat) Line(columnWidths [8]int) {
 	for i, width := range columnWidths {
 		if i == 0 {
 			fmt.Print(strings.Repeat("-", width+1))
@@ -662,14 +1199,15 @@ func (cs *CourseStat) Add(stat CourseStat) {
 	cs.Errors += stat.Errors
 }
 
-func NewCourseStat(title string, total, stub, incomplete, complete, errors int) CourseStat {
-	return CourseStat{Title: title, Total: total, Stub: stub, Incomplete: incomplete, Complete: complete, Errors: errors}
+func NewCourseStat(title string, state State, total, stub, incomplete, complete, errors int) CourseStat {
+	return CourseStat{Title: title, State: state, Total: total, Stub: stub, Incomplete: incomplete, Complete: complete, Errors: errors}
 }
 
 func (c Courses) Stats() {
-	columnWidths := [7]int{15, 5, 4, 10, 8, 6, 7}
-	columnColors := [7]Color{cliBold, cliBold, cliBlue, cliYellow, cliGreen, cliRed, cliBold}
-	totalStat := NewCourseStat("Total", 0, 0, 0, 0, 0)
+	columnWidths := [8]int{15, 10, 5, 4, 10, 8, 6, 7}
+	columnColors := [8]Color{cliBold, cliBold, cliBold, cliBlue, cliYellow, cliGreen, cliRed, cliBold}
+	totalStat := NewCourseStat("Total", "", 0, 0, 0, 0, 0)
+	courseStates := make([]State, 0, len(c))
 
 	totalStat.PrintHead(columnWidths, columnColors)
 	totalStat.Line(columnWidths)
@@ -678,13 +1216,16 @@ func (c Courses) Stats() {
 	for _, course := range c {
 		courseAll, courseStub, courseIncomplete, courseComplete, courseErrors := course.Stats()
 
-		newStats := NewCourseStat(course.Title, courseAll, courseStub, courseIncomplete, courseComplete, courseErrors)
+		newStats := NewCourseStat(course.Title, course.CalculateState(), courseAll, courseStub, courseIncomplete, courseComplete, courseErrors)
+		courseStates = append(courseStates, newStats.State)
 
 		stats = append(stats, newStats)
 
 		totalStat.Add(newStats)
 	}
 
+	totalStat.State = combineStates(courseStates)
+
 	for _, stat := range stats {
 		stat.Print(columnWidths, columnColors, totalStat.Total)
 	}
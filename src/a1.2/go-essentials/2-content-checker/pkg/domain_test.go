@@ -1,9 +1,12 @@
 package pkg
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCourses_Add(t *testing.T) {
@@ -374,3 +377,492 @@ func Test_slugify(t *testing.T) {
 		})
 	}
 }
+
+func TestContent_WeightNumber(t *testing.T) {
+	tests := []struct {
+		name       string
+		weight     string
+		wantWeight int
+		wantOK     bool
+	}{
+		{
+			name:       "number",
+			weight:     "20",
+			wantWeight: 20,
+			wantOK:     true,
+		},
+		{
+			name:       "missing",
+			weight:     "",
+			wantWeight: 0,
+			wantOK:     false,
+		},
+		{
+			name:       "not a number",
+			weight:     "2O",
+			wantWeight: 0,
+			wantOK:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			gotWeight, gotOK := Content{Weight: tt.weight}.WeightNumber()
+
+			// verify
+			assert.Equal(t, tt.wantWeight, gotWeight)
+			assert.Equal(t, tt.wantOK, gotOK)
+		})
+	}
+}
+
+func TestContent_GetIssues_Weight(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   Content
+		filePath  string
+		wantIssue string
+	}{
+		{
+			name:      "missing weight",
+			content:   Content{Body: DefaultBody{}},
+			filePath:  "foo.md",
+			wantIssue: "weight is missing",
+		},
+		{
+			name:      "non-numeric weight",
+			content:   Content{Weight: "abc", Body: DefaultBody{}},
+			filePath:  "abc-.md",
+			wantIssue: "weight is not a number: abc",
+		},
+		{
+			name:      "non-numeric index weight",
+			content:   Content{Weight: "abc", Body: &IndexBody{}},
+			filePath:  "_index.md",
+			wantIssue: "weight is not a number: abc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := tt.content.GetIssues(tt.filePath)
+
+			// verify
+			assert.Contains(t, got, tt.wantIssue)
+		})
+	}
+
+	t.Run("index page without weight", func(t *testing.T) {
+		// execute
+		got := Content{Body: &IndexBody{}}.GetIssues("_index.md")
+
+		// verify
+		assert.NotContains(t, got, "weight is missing")
+	})
+}
+
+func TestChapter_Prepare_Weights(t *testing.T) {
+	chapter := &Chapter{
+		Title: "bar",
+		Pages: Pages{
+			{FilePath: "30-c.md", Content: Content{Weight: "30", Body: DefaultBody{}}},
+			{FilePath: "x-d.md", Content: Content{Weight: "x", Body: DefaultBody{}}},
+			{FilePath: "100-a.md", Content: Content{Weight: "100", Body: DefaultBody{}}},
+			{FilePath: "_index.md", Content: Content{Weight: "10", Body: &IndexBody{}}},
+			{FilePath: "30-b.md", Content: Content{Weight: "30", Body: DefaultBody{}}},
+		},
+	}
+
+	// execute
+	chapter.Prepare()
+
+	// verify
+	var filePaths []string
+	for _, page := range chapter.Pages {
+		filePaths = append(filePaths, page.FilePath)
+	}
+
+	assert.Equal(t, []string{"_index.md", "30-c.md", "30-b.md", "100-a.md", "x-d.md"}, filePaths)
+	assert.Nil(t, chapter.Pages[1].Issues)
+	assert.Equal(t, []string{"duplicate weight in chapter: 30, also used by 30-c.md"}, chapter.Pages[2].Issues)
+}
+
+func TestChapter_Prepare_CompleteState(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages Pages
+		want  State
+	}{
+		{
+			name: "complete pages",
+			pages: Pages{
+				{FilePath: "_index.md", Content: Content{Body: &IndexBody{}}},
+				{FilePath: "10-a.md", Content: Content{State: Complete, Body: DefaultBody{}}},
+			},
+			want: Complete,
+		},
+		{
+			name: "incomplete page",
+			pages: Pages{
+				{FilePath: "_index.md", Content: Content{Body: &IndexBody{}}},
+				{FilePath: "10-a.md", Content: Content{State: Complete, Body: DefaultBody{}}},
+				{FilePath: "20-b.md", Content: Content{State: Stub, Body: DefaultBody{}}},
+			},
+			want: "",
+		},
+		{
+			name: "no pages",
+			pages: Pages{
+				{FilePath: "_index.md", Content: Content{Body: &IndexBody{}}},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chapter := &Chapter{Title: "foo", Pages: tt.pages}
+
+			// execute
+			chapter.Prepare()
+
+			// verify
+			assert.Equal(t, tt.want, chapter.Pages[0].Content.Body.(*IndexBody).CompleteState)
+		})
+	}
+}
+
+func TestCourse_Prepare_Weights(t *testing.T) {
+	newChapter := func(title, weight string) *Chapter {
+		return &Chapter{
+			Title: title,
+			Pages: Pages{{FilePath: title + "/_index.md", Content: Content{Weight: weight, Body: &IndexBody{}}}},
+		}
+	}
+
+	course := Course{
+		Title: "foo",
+		Chapters: Chapters{
+			{Title: "no-index"},
+			newChapter("second", "20"),
+			newChapter("first", "10"),
+			newChapter("also-second", "20"),
+		},
+	}
+
+	// execute
+	course.Prepare()
+
+	// verify
+	var titles []string
+	for _, chapter := range course.Chapters {
+		titles = append(titles, chapter.Title)
+	}
+
+	assert.Equal(t, []string{"first", "second", "also-second", "no-index"}, titles)
+	assert.Equal(t, []string{"duplicate weight in course: 20, also used by second/_index.md"}, course.Chapters[2].Pages[0].Issues)
+}
+
+func TestIndexBody_GetIssues(t *testing.T) {
+	body := &IndexBody{
+		HasEpisodes: true,
+		Episodes: []Episode{
+			{Title: "Foo", Link: "/a1/go/foo/"},
+			{Title: "Bar"},
+			{Title: "Foo again", Link: "/a1/go/foo/#intro"},
+		},
+	}
+
+	// execute
+	got := body.GetIssues(Incomplete)
+
+	// verify
+	assert.Equal(t, []string{"episode without a link: Bar", "duplicate episode: /a1/go/foo/#intro"}, got)
+}
+
+func TestChapter_Prepare_Episodes(t *testing.T) {
+	newPage := func(weight, slug string) Page {
+		return Page{FilePath: weight + "-" + slug + ".md", Content: Content{Weight: weight, Slug: slug, Body: DefaultBody{}}}
+	}
+
+	tests := []struct {
+		name     string
+		episodes []Episode
+		want     []string
+	}{
+		{
+			name: "in sync",
+			episodes: []Episode{
+				{Title: "Foo", Link: "/a1/go/foo/"},
+				{Title: "Bar", Link: `{{< ref "20-bar.md" >}}`},
+				{Title: "Baz", Link: "baz"},
+			},
+			want: nil,
+		},
+		{
+			name: "missing, non-existent and out of order",
+			episodes: []Episode{
+				{Title: "Baz", Link: "/a1/go/baz/"},
+				{Title: "Foo", Link: "/a1/go/foo/"},
+				{Title: "Qux", Link: "/a1/go/qux/"},
+			},
+			want: []string{
+				"episodes are not in the order of the page weights, first out of order: /a1/go/foo/",
+				"episode links to a non-existent page: /a1/go/qux/",
+				"episode is missing for page: 20-bar.md",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chapter := &Chapter{
+				Title: "go",
+				Pages: Pages{
+					newPage("30", "baz"),
+					{FilePath: "_index.md", Content: Content{Body: &IndexBody{HasEpisodes: true, Episodes: tt.episodes}}},
+					newPage("10", "foo"),
+					newPage("20", "bar"),
+				},
+			}
+
+			// execute
+			chapter.Prepare()
+
+			// verify
+			assert.Equal(t, tt.want, chapter.GetIndex().Issues)
+		})
+	}
+}
+
+func TestPracticeBody_GetIssues(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  PracticeBody
+		state State
+		want  []string
+	}{
+		{
+			name: "valid",
+			body: PracticeBody{
+				HasDescription:           true,
+				HasRecommendedChallenges: true,
+				RecommendedChallenges:    Challenges{{Title: "Foo", Link: "foo"}},
+				SectionTitles:            []string{sectionDescription, sectionRecommendedChallenges},
+			},
+			state: Incomplete,
+			want:  nil,
+		},
+		{
+			name: "state mismatch",
+			body: PracticeBody{
+				HasDescription: true,
+				SectionTitles:  []string{sectionDescription},
+			},
+			state: Complete,
+			want:  []string{"state mismatch. got: complete, want: incomplete"},
+		},
+		{
+			name: "wrong order",
+			body: PracticeBody{
+				HasDescription: true,
+				SectionTitles:  []string{sectionDescription, sectionAdditionalChallenges, sectionRecommendedChallenges},
+			},
+			state: Incomplete,
+			want:  []string{"sections are not in the correct order, first out of order: recommended challenges"},
+		},
+		{
+			name: "malformed challenges",
+			body: PracticeBody{
+				HasDescription:           true,
+				HasRecommendedChallenges: true,
+				HasAdditionalChallenges:  true,
+				RecommendedChallenges:    Challenges{{Issues: []string{"empty challenge entry"}}},
+				SectionTitles:            []string{sectionDescription, sectionRecommendedChallenges, sectionAdditionalChallenges},
+			},
+			state: Complete,
+			want: []string{
+				"no challenges found in the additional challenges section",
+				"empty challenge entry",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := tt.body.GetIssues(tt.state)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCourse_CalculateState(t *testing.T) {
+	newChapter := func(states ...State) *Chapter {
+		chapter := &Chapter{Pages: Pages{{FilePath: "_index.md", Content: Content{State: Complete, Body: &IndexBody{}}}}}
+		for _, state := range states {
+			chapter.Pages = append(chapter.Pages, Page{FilePath: "page.md", Content: Content{State: state, Body: DefaultBody{}}})
+		}
+
+		return chapter
+	}
+
+	tests := []struct {
+		name     string
+		chapters Chapters
+		want     State
+	}{
+		{
+			name:     "no chapters",
+			chapters: nil,
+			want:     Stub,
+		},
+		{
+			name:     "all complete",
+			chapters: Chapters{newChapter(Complete, Complete), newChapter(Complete)},
+			want:     Complete,
+		},
+		{
+			name:     "all stub",
+			chapters: Chapters{newChapter(Stub, Stub), newChapter(), newChapter("")},
+			want:     Stub,
+		},
+		{
+			name:     "complete and stub chapters",
+			chapters: Chapters{newChapter(Complete), newChapter(Stub)},
+			want:     Incomplete,
+		},
+		{
+			name:     "incomplete page",
+			chapters: Chapters{newChapter(Complete, Incomplete)},
+			want:     Incomplete,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course := Course{Title: "foo", Chapters: tt.chapters}
+
+			// execute
+			got := course.CalculateState()
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCourse_GetIssues(t *testing.T) {
+	completeChapter := &Chapter{Pages: Pages{{FilePath: "page.md", Content: Content{State: Complete, Body: DefaultBody{}}}}}
+
+	tests := []struct {
+		name   string
+		course Course
+		want   []string
+	}{
+		{
+			name:   "missing index",
+			course: Course{Title: "foo"},
+			want:   []string{"course index page is missing"},
+		},
+		{
+			name: "valid",
+			course: Course{
+				Title:    "foo",
+				Index:    &Page{Content: Content{Title: "Foo", Description: "Bar", Audience: All, State: Complete}},
+				Chapters: Chapters{completeChapter},
+			},
+			want: nil,
+		},
+		{
+			name: "invalid",
+			course: Course{
+				Title: "foo",
+				Index: &Page{Content: Content{Audience: "nobody", State: Complete}},
+			},
+			want: []string{
+				"course title is missing",
+				"course description is missing",
+				"invalid audience: nobody",
+				"state mismatch. got: complete, want: stub",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := tt.course.GetIssues()
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCourse_GetDocumentErrors(t *testing.T) {
+	var courses Courses
+
+	for _, filePath := range []string{"content/foo/bar/10-baz.md", "content/foo/bar/20-baz.md"} {
+		content, err := ParseMarkdown("+++\ntitle = 'Baz'\nslug = 'baz'\nweight = 10\nstate = 'stub'\naudience = \"all\"\naudienceImportance = \"important\"\n+++\n")
+		require.NoError(t, err)
+
+		courses = courses.Add(filePath, "foo", "bar", filepath.Base(filePath), content)
+	}
+
+	courses = courses.AddIndex("content/foo/_index.md", "foo", Content{Title: "Foo", Audience: All, State: Complete, Body: DefaultBody{}})
+
+	// execute
+	got := courses[0].GetDocumentErrors()
+
+	// verify, the duplicate weight and the course state are not checked
+	assert.Equal(t, []string{
+		"content/foo/_index.md - course description is missing",
+		"content/foo/bar/10-baz.md - summary section is missing",
+		"content/foo/bar/10-baz.md - topics section is missing",
+		"content/foo/bar/20-baz.md - summary section is missing",
+		"content/foo/bar/20-baz.md - topics section is missing",
+		"content/foo/bar/20-baz.md - file name is not prefixed with the weight of the page",
+		"content/foo/bar/20-baz.md - file name does not match the dash joined weight and slug",
+	}, got)
+}
+
+func TestCourses_AddIndex(t *testing.T) {
+	content := Content{Title: "Foo", Body: DefaultBody{}}
+
+	// execute
+	got := Courses{{Title: "bar"}}.AddIndex("content/foo/_index.md", "foo", content).AddIndex("content/bar/_index.md", "bar", content)
+
+	// verify
+	assert.Equal(t, Courses{
+		{Title: "bar", Index: &Page{FilePath: "content/bar/_index.md", Title: "_index.md", Content: content}},
+		{Title: "foo", Index: &Page{FilePath: "content/foo/_index.md", Title: "_index.md", Content: content}},
+	}, got)
+}
+
+// testPage returns a page with a default body, the slug is also used as its title
+func testPage(filePath, slug string, state State, prerequisites ...string) Page {
+	return Page{FilePath: filePath, Content: Content{Title: slug, Slug: slug, State: state, Prerequisites: prerequisites, Body: DefaultBody{}}}
+}
+
+// parseTestPage returns a page with the parsed content of a markdown file
+func parseTestPage(t *testing.T, filePath, rawContent string) Page {
+	t.Helper()
+
+	content, err := ParseMarkdown(rawContent)
+	require.NoError(t, err)
+
+	return Page{FilePath: filePath, Content: content}
+}
+
+// testCourses adds the pages to courses in the given order, the course and the chapter of a page are the parent
+// directories of its file
+func testCourses(pages ...Page) Courses {
+	var courses Courses
+
+	for _, page := range pages {
+		chapterDir, pageFN := filepath.Split(page.FilePath)
+		courseDir, chapterFN := filepath.Split(strings.TrimSuffix(chapterDir, "/"))
+
+		courses = courses.Add(page.FilePath, filepath.Base(courseDir), chapterFN, pageFN, page.Content)
+	}
+
+	return courses
+}
@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PlanEpisodesSync regenerates the episodes section of a chapter's index page from the pages of the chapter. Links of
+// episodes already listed are kept, new links follow the format of the existing ones.
+func PlanEpisodesSync(files map[string]string, chapterDir string) (FileChanges, error) {
+	chapterDir = filepath.Clean(chapterDir)
+	indexPath := filepath.Join(chapterDir, "_index.md")
+
+	rawIndex, ok := files[indexPath]
+	if !ok {
+		return nil, fmt.Errorf("index page not found: %s", indexPath)
+	}
+
+	indexContent, err := ParseMarkdown(rawIndex)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse markdown: %s, err: %w", indexPath, err)
+	}
+
+	chapter := &Chapter{Title: filepath.Base(chapterDir)}
+	for filePath, rawContent := range files {
+		if filepath.Dir(filePath) != chapterDir || filePath == indexPath {
+			continue
+		}
+
+		// translations are listed by the translated index pages
+		if _, language := SplitLanguage(filepath.Base(filePath)); language != "" {
+			continue
+		}
+
+		content, err := ParseMarkdown(rawContent)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse markdown: %s, err: %w", filePath, err)
+		}
+
+		chapter.Pages = chapter.Pages.Add(filePath, filepath.Base(filePath), content)
+	}
+
+	chapter.sortPages()
+
+	links := make(map[int]string, len(chapter.Pages))
+	linkTemplate := fmt.Sprintf("/%s/%s/%%s/", filepath.Base(filepath.Dir(chapterDir)), chapter.Title)
+	linkByFileName := false
+
+	if body, ok := indexContent.Body.(*IndexBody); ok {
+		for _, episode := range body.Episodes {
+			target := episode.Target()
+
+			position := chapter.findPage(target)
+			if position < 0 {
+				continue
+			}
+
+			links[position] = episode.Link
+
+			// new links should reference pages the same way as the existing ones, either by slug or by file name
+			idx := strings.LastIndex(episode.Link, target)
+			suffix := withoutFragment(episode.Link[idx+len(target):])
+			linkTemplate = strings.Replace(episode.Link[:idx], "%", "%%", -1) + "%s" + strings.Replace(suffix, "%", "%%", -1)
+			linkByFileName = target != chapter.Pages[position].Content.Slug
+		}
+	}
+
+	rows := make([]string, 0, len(chapter.Pages))
+	for i, page := range chapter.Pages {
+		link, ok := links[i]
+		if !ok {
+			target := page.Content.Slug
+			if linkByFileName {
+				target = strings.TrimSuffix(filepath.Base(page.FilePath), ".md")
+			}
+
+			link = fmt.Sprintf(linkTemplate, target)
+		}
+
+		rows = append(rows, fmt.Sprintf("- [%s](%s)", page.Content.Title, link))
+	}
+
+	newIndex := replaceSection(rawIndex, sectionEpisodes, strings.Join(rows, EOL))
+	if newIndex == rawIndex {
+		return nil, nil
+	}
+
+	return FileChanges{{FilePath: indexPath, Before: rawIndex, After: newIndex}}, nil
+}
+
+// withoutFragment removes the fragment and the query from the part of a link following its target, the closing quote of
+// a ref shortcode is kept
+func withoutFragment(suffix string) string {
+	idx := strings.IndexAny(suffix, "#?")
+	if idx < 0 {
+		return suffix
+	}
+
+	end := strings.Index(suffix[idx:], `"`)
+	if end < 0 {
+		return suffix[:idx]
+	}
+
+	return suffix[:idx] + suffix[idx+end:]
+}
@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func episodesTestPage(weight, slug, title string) string {
+	return "+++\ntitle = '" + title + "'\nweight = " + weight + "\nslug = '" + slug + "'\n+++\n"
+}
+
+func TestPlanEpisodesSync(t *testing.T) {
+	pages := map[string]string{
+		"content/a1/go/10-foo.md": episodesTestPage("10", "foo", "Foo"),
+		"content/a1/go/20-bar.md": episodesTestPage("20", "bar", "Bar"),
+		"content/a1/go/30-baz.md": episodesTestPage("30", "baz", "Baz"),
+	}
+
+	tests := []struct {
+		name  string
+		index string
+		want  string
+	}{
+		{
+			name:  "missing section",
+			index: "+++\ntitle = 'Go'\n+++\n\nIntro.\n",
+			want:  "+++\ntitle = 'Go'\n+++\n\nIntro.\n\nEpisodes\n--------\n\n- [Foo](/a1/go/foo/)\n- [Bar](/a1/go/bar/)\n- [Baz](/a1/go/baz/)\n",
+		},
+		{
+			name:  "keeps existing links and their format",
+			index: "+++\ntitle = 'Go'\n+++\n\n## Episodes\n\n- [Bar](https://example.com/go/bar/)\n- [Qux](/a1/go/qux/)\n\n## Notes\n\nfoo\n",
+			want:  "+++\ntitle = 'Go'\n+++\n\n## Episodes\n\n- [Foo](https://example.com/go/foo/)\n- [Bar](https://example.com/go/bar/)\n- [Baz](https://example.com/go/baz/)\n\n## Notes\n\nfoo\n",
+		},
+		{
+			name:  "ref shortcodes",
+			index: "+++\ntitle = 'Go'\n+++\n\nEpisodes\n--------\n\n- [Foo]({{< ref \"10-foo.md\" >}})\n",
+			want:  "+++\ntitle = 'Go'\n+++\n\nEpisodes\n--------\n\n- [Foo]({{< ref \"10-foo.md\" >}})\n- [Bar]({{< ref \"20-bar.md\" >}})\n- [Baz]({{< ref \"30-baz.md\" >}})\n",
+		},
+		{
+			name:  "fragments are not copied",
+			index: "+++\ntitle = 'Go'\n+++\n\n## Episodes\n\n- [Foo](/a1/go/foo/#intro)\n",
+			want:  "+++\ntitle = 'Go'\n+++\n\n## Episodes\n\n- [Foo](/a1/go/foo/#intro)\n- [Bar](/a1/go/bar/)\n- [Baz](/a1/go/baz/)\n",
+		},
+		{
+			name:  "fragments of ref shortcodes are not copied",
+			index: "+++\ntitle = 'Go'\n+++\n\n## Episodes\n\n- [Bar]({{< ref \"20-bar.md#intro\" >}})\n",
+			want:  "+++\ntitle = 'Go'\n+++\n\n## Episodes\n\n- [Foo]({{< ref \"10-foo.md\" >}})\n- [Bar]({{< ref \"20-bar.md#intro\" >}})\n- [Baz]({{< ref \"30-baz.md\" >}})\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"content/a1/go/_index.md": tt.index}
+			for filePath, rawContent := range pages {
+				files[filePath] = rawContent
+			}
+
+			// execute
+			got, err := PlanEpisodesSync(files, "content/a1/go")
+			require.NoError(t, err)
+
+			// verify
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.want, got[0].After)
+		})
+	}
+
+	t.Run("in sync", func(t *testing.T) {
+		files := map[string]string{
+			"content/a1/go/_index.md": "+++\ntitle = 'Go'\n+++\n\nEpisodes\n--------\n\n- [Foo](/a1/go/foo/)\n",
+			"content/a1/go/10-foo.md": episodesTestPage("10", "foo", "Foo"),
+		}
+
+		// execute
+		got, err := PlanEpisodesSync(files, "content/a1/go")
+		require.NoError(t, err)
+
+		// verify
+		assert.Empty(t, got)
+	})
+
+	t.Run("translations", func(t *testing.T) {
+		files := map[string]string{
+			"content/a1/go/_index.md":    "+++\ntitle = 'Go'\n+++\n\nEpisodes\n--------\n\n- [Foo](/a1/go/foo/)\n",
+			"content/a1/go/_index.pl.md": "+++\ntitle = 'Go PL'\n+++\n",
+			"content/a1/go/10-foo.md":    episodesTestPage("10", "foo", "Foo"),
+			"content/a1/go/10-foo.pl.md": episodesTestPage("10", "foo", "Fu"),
+			"content/a1/go/20-bar.de.md": episodesTestPage("20", "bar", "Bar DE"),
+		}
+
+		// execute
+		got, err := PlanEpisodesSync(files, "content/a1/go")
+		require.NoError(t, err)
+
+		// verify
+		assert.Empty(t, got)
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		// execute
+		_, err := PlanEpisodesSync(pages, "content/a1/go")
+
+		// verify
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,362 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StateInput is a value of the page the calculated state depends on
+type StateInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Satisfied is true if the value is the one needed for a complete page
+	Satisfied bool `json:"satisfied"`
+}
+
+// StateCheck is a requirement of a state
+type StateCheck struct {
+	Requirement string `json:"requirement"`
+	Satisfied   bool   `json:"satisfied"`
+	// Fix tells what to add to the page to satisfy the requirement
+	Fix string `json:"fix,omitempty"`
+}
+
+// StateStep is a state above stub and the requirements of reaching it
+type StateStep struct {
+	State State `json:"state"`
+	// AnyOf is true if satisfying a single check is enough, all checks have to be satisfied otherwise
+	AnyOf  bool         `json:"anyOf,omitempty"`
+	Checks []StateCheck `json:"checks"`
+}
+
+func (s StateStep) Reached() bool {
+	satisfied := 0
+	for _, check := range s.Checks {
+		if check.Satisfied {
+			satisfied++
+		}
+	}
+
+	if s.AnyOf {
+		return satisfied > 0
+	}
+
+	return satisfied == len(s.Checks)
+}
+
+// StateExplanation is the decision trace of the calculated state of a page
+type StateExplanation struct {
+	FilePath   string       `json:"filePath"`
+	Kind       PageKind     `json:"kind"`
+	State      State        `json:"state"`
+	Calculated State        `json:"calculated"`
+	Inputs     []StateInput `json:"inputs"`
+	// Steps are the states above stub, in increasing order
+	Steps []StateStep `json:"steps"`
+}
+
+// Next returns the first state which is not reached, false is returned for complete pages
+func (e StateExplanation) Next() (StateStep, bool) {
+	for _, step := range e.Steps {
+		if !step.Reached() {
+			return step, true
+		}
+	}
+
+	return StateStep{}, false
+}
+
+// ToComplete returns what to add to the page to reach the complete state
+func (e StateExplanation) ToComplete() []string {
+	var fixes []string
+
+	for _, step := range e.Steps {
+		if step.State != Complete {
+			continue
+		}
+
+		for _, check := range step.Checks {
+			if !check.Satisfied {
+				fixes = append(fixes, check.Fix)
+			}
+		}
+	}
+
+	return fixes
+}
+
+// ExplainState returns the decision trace of the calculated state of the page with the given path, translations
+// included
+func (c Courses) ExplainState(filePath string) (StateExplanation, bool) {
+	for _, course := range c {
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				if page.FilePath == filePath {
+					return explainState(page, chapter.Pages), true
+				}
+
+				for _, translation := range page.Translations {
+					if translation.FilePath == filePath {
+						return explainState(translation, chapter.Pages), true
+					}
+				}
+			}
+		}
+	}
+
+	return StateExplanation{}, false
+}
+
+// ExplainState checks the whole site and returns the decision trace of the calculated state of the given page
+func (c *Checker) ExplainState(reportedPath string) (StateExplanation, error) {
+	filePath, err := c.fsPath(reportedPath)
+	if err != nil {
+		return StateExplanation{}, err
+	}
+
+	result, err := c.Check()
+	if err != nil {
+		return StateExplanation{}, err
+	}
+
+	explanation, ok := result.Courses.ExplainState(c.reportedPath(filePath))
+	if !ok {
+		return StateExplanation{}, fmt.Errorf("page not found: %s", reportedPath)
+	}
+
+	return explanation, nil
+}
+
+func explainState(page Page, chapterPages Pages) StateExplanation {
+	explanation := StateExplanation{
+		FilePath:   page.FilePath,
+		State:      page.Content.State,
+		Calculated: page.Content.Body.CalculateState(),
+	}
+
+	switch body := page.Content.Body.(type) {
+	case DefaultBody:
+		explanation.Kind = DefaultPage
+		explanation.Inputs, explanation.Steps = explainDefaultBody(body)
+	case *IndexBody:
+		explanation.Kind = IndexPage
+		explanation.Inputs, explanation.Steps = explainIndexBody(body, chapterPages)
+	case *PracticeBody:
+		explanation.Kind = PracticePage
+		explanation.Inputs, explanation.Steps = explainPracticeBody(body)
+	}
+
+	return explanation
+}
+
+func explainDefaultBody(db DefaultBody) ([]StateInput, []StateStep) {
+	unchecked := 0
+	for _, video := range db.RelatedVideos {
+		if video.Badge == Unchecked {
+			unchecked++
+		}
+	}
+
+	inputs := []StateInput{
+		{Name: "summary section", Value: yesNo(db.HasSummary), Satisfied: db.HasSummary},
+		{Name: "exercises section", Value: yesNo(db.HasExercises), Satisfied: db.HasExercises},
+		{Name: "main video", Value: string(db.MainVideo), Satisfied: db.MainVideo == VideoPresent},
+		{Name: "related videos with unchecked badge", Value: fmt.Sprint(unchecked), Satisfied: unchecked == 0},
+		{Name: tagUsefulWithoutVideo + " tag", Value: yesNo(db.UsefulWithoutVideo), Satisfied: db.UsefulWithoutVideo},
+		{Name: "related videos with alternative badge", Value: yesNo(db.RelatedVideos.Has(Alternative)), Satisfied: db.RelatedVideos.Has(Alternative)},
+	}
+
+	videoFix := "add a youtube shortcode to the main video section"
+	switch db.MainVideo {
+	case VideoMissing, VideoReallyMissing:
+		videoFix = "replace the main-missing marker of the main video section with a youtube shortcode"
+	case VideoProblem:
+		if db.MainVideoProblem != nil && db.MainVideoProblem.Code != MainVideoNoShortcode {
+			videoFix = "fix the main video section: " + db.MainVideoProblem.Issue()
+		}
+	}
+
+	steps := []StateStep{
+		{
+			State: Incomplete,
+			AnyOf: true,
+			Checks: []StateCheck{
+				{Requirement: "main video is present", Satisfied: db.MainVideo == VideoPresent, Fix: videoFix},
+				{Requirement: "a related video has the alternative badge", Satisfied: db.RelatedVideos.Has(Alternative), Fix: "add a related video with the badge-alternative shortcode"},
+				{Requirement: "page is tagged " + tagUsefulWithoutVideo, Satisfied: db.UsefulWithoutVideo, Fix: "add the " + tagUsefulWithoutVideo + " tag"},
+			},
+		},
+		{
+			State: Complete,
+			Checks: []StateCheck{
+				{Requirement: "summary section is present", Satisfied: db.HasSummary, Fix: "add a summary section"},
+				{Requirement: "exercises section is present", Satisfied: db.HasExercises, Fix: "add a non-empty exercises section"},
+				{Requirement: "no related video has the unchecked badge", Satisfied: unchecked == 0, Fix: fmt.Sprintf("check the related videos with the unchecked badge (%d)", unchecked)},
+				{
+					Requirement: "main video is present, or the page is tagged " + tagUsefulWithoutVideo + " and the main video is not marked missing",
+					Satisfied:   db.MainVideo == VideoPresent || db.UsefulWithoutVideo && db.MainVideo != VideoMissing && db.MainVideo != VideoReallyMissing,
+					Fix:         videoFix,
+				},
+			},
+		},
+	}
+
+	return inputs, steps
+}
+
+func explainIndexBody(ib *IndexBody, chapterPages Pages) ([]StateInput, []StateStep) {
+	var (
+		total      int
+		incomplete []string
+	)
+
+	for _, page := range chapterPages {
+		if page.IsIndex() {
+			continue
+		}
+
+		total++
+
+		if page.GetState() != Complete {
+			incomplete = append(incomplete, page.FilePath)
+		}
+	}
+
+	pagesComplete := total > 0 && len(incomplete) == 0
+
+	pagesFix := "add pages to the chapter"
+	if len(incomplete) > 0 {
+		pagesFix = "complete the pages: " + strings.Join(incomplete, ", ")
+	}
+
+	inputs := []StateInput{
+		{Name: "episodes section", Value: yesNo(ib.HasEpisodes), Satisfied: ib.HasEpisodes},
+		{Name: "complete pages in chapter", Value: fmt.Sprintf("%d/%d", total-len(incomplete), total), Satisfied: pagesComplete},
+	}
+
+	steps := []StateStep{
+		{
+			State:  Incomplete,
+			Checks: []StateCheck{{Requirement: "episodes section is present", Satisfied: ib.HasEpisodes, Fix: "add an episodes section"}},
+		},
+		{
+			State: Complete,
+			Checks: []StateCheck{
+				{Requirement: "episodes section is present", Satisfied: ib.HasEpisodes, Fix: "add an episodes section"},
+				{Requirement: "all pages of the chapter are complete", Satisfied: pagesComplete, Fix: pagesFix},
+			},
+		},
+	}
+
+	return inputs, steps
+}
+
+func explainPracticeBody(pb *PracticeBody) ([]StateInput, []StateStep) {
+	inputs := []StateInput{
+		{Name: "description section", Value: yesNo(pb.HasDescription), Satisfied: pb.HasDescription},
+		{Name: "recommended challenges section", Value: yesNo(pb.HasRecommendedChallenges), Satisfied: pb.HasRecommendedChallenges},
+		{Name: "additional challenges section", Value: yesNo(pb.HasAdditionalChallenges), Satisfied: pb.HasAdditionalChallenges},
+	}
+
+	descriptionCheck := StateCheck{Requirement: "description section is present", Satisfied: pb.HasDescription, Fix: "add a description section"}
+
+	steps := []StateStep{
+		{
+			State:  Incomplete,
+			Checks: []StateCheck{descriptionCheck},
+		},
+		{
+			State: Complete,
+			Checks: []StateCheck{
+				descriptionCheck,
+				{Requirement: "recommended challenges section is present", Satisfied: pb.HasRecommendedChallenges, Fix: "add a recommended challenges section"},
+				{Requirement: "additional challenges section is present", Satisfied: pb.HasAdditionalChallenges, Fix: "add an additional challenges section"},
+			},
+		},
+	}
+
+	return inputs, steps
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+
+	return "no"
+}
+
+func checkMark(satisfied bool) string {
+	if satisfied {
+		return string(cliGreen) + "[x]" + string(cliReset)
+	}
+
+	return string(cliRed) + "[ ]" + string(cliReset)
+}
+
+func (e StateExplanation) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s%s%s (%s)\n", cliBold, e.FilePath, cliReset, e.Kind))
+	sb.WriteString(fmt.Sprintf("state: %s%s%s, calculated: %s%s%s\n", stateColor(e.State), e.State, cliReset, stateColor(e.Calculated), e.Calculated, cliReset))
+
+	sb.WriteString(EOL + "Inputs:" + EOL)
+
+	for _, input := range e.Inputs {
+		sb.WriteString(fmt.Sprintf("  %s %s: %s\n", checkMark(input.Satisfied), input.Name, input.Value))
+	}
+
+	for _, step := range e.Steps {
+		status, mode := "reached", "all of"
+		if !step.Reached() {
+			status = "blocked"
+		}
+
+		if step.AnyOf {
+			mode = "any of"
+		}
+
+		sb.WriteString(fmt.Sprintf("\n%s: %s, %s\n", step.State, status, mode))
+
+		for _, check := range step.Checks {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", checkMark(check.Satisfied), check.Requirement))
+		}
+	}
+
+	if next, ok := e.Next(); ok {
+		var blocking []string
+
+		for _, check := range next.Checks {
+			if !check.Satisfied {
+				blocking = append(blocking, check.Requirement)
+			}
+		}
+
+		separator := " and "
+		if next.AnyOf {
+			separator = " or "
+		}
+
+		sb.WriteString(fmt.Sprintf("\n%s needs: %s\n", next.State, strings.Join(blocking, separator)))
+	}
+
+	if fixes := e.ToComplete(); len(fixes) > 0 {
+		sb.WriteString(EOL + "To reach complete:" + EOL)
+
+		for _, fix := range fixes {
+			sb.WriteString("  - " + fix + EOL)
+		}
+	}
+
+	return sb.String()
+}
+
+func (e StateExplanation) JSON() (string, error) {
+	raw, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw) + EOL, nil
+}
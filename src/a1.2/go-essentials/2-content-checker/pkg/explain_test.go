@@ -0,0 +1,112 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateExplanation_Next(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         Body
+		wantNext     State
+		wantBlocking []string
+		wantFixes    []string
+	}{
+		{
+			name:         "stub page needs a video",
+			body:         DefaultBody{MainVideo: VideoProblem, HasSummary: true},
+			wantNext:     Incomplete,
+			wantBlocking: []string{"main video is present", "a related video has the alternative badge", "page is tagged useful-without-video"},
+			wantFixes: []string{
+				"add a non-empty exercises section",
+				"add a youtube shortcode to the main video section",
+			},
+		},
+		{
+			name:         "missing marker blocks complete",
+			body:         DefaultBody{MainVideo: VideoMissing, HasSummary: true, HasExercises: true, UsefulWithoutVideo: true},
+			wantNext:     Complete,
+			wantBlocking: []string{"main video is present, or the page is tagged useful-without-video and the main video is not marked missing"},
+			wantFixes:    []string{"replace the main-missing marker of the main video section with a youtube shortcode"},
+		},
+		{
+			name:         "unchecked badge blocks complete",
+			body:         DefaultBody{MainVideo: VideoPresent, HasSummary: true, HasExercises: true, RelatedVideos: RelatedVideos{{Badge: Unchecked}, {Badge: Alternative}}},
+			wantNext:     Complete,
+			wantBlocking: []string{"no related video has the unchecked badge"},
+			wantFixes:    []string{"check the related videos with the unchecked badge (1)"},
+		},
+		{
+			name: "complete page",
+			body: DefaultBody{MainVideo: VideoPresent, HasSummary: true, HasExercises: true},
+		},
+		{
+			name:         "practice without challenges",
+			body:         &PracticeBody{HasDescription: true, HasRecommendedChallenges: true},
+			wantNext:     Complete,
+			wantBlocking: []string{"additional challenges section is present"},
+			wantFixes:    []string{"add an additional challenges section"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation := explainState(Page{FilePath: "foo.md", Content: Content{Body: tt.body}}, nil)
+
+			// execute
+			next, ok := explanation.Next()
+
+			// verify
+			assert.Equal(t, tt.wantNext, next.State)
+			assert.Equal(t, tt.wantNext != "", ok)
+			assert.Equal(t, tt.wantFixes, explanation.ToComplete())
+
+			var blocking []string
+			for _, check := range next.Checks {
+				if !check.Satisfied {
+					blocking = append(blocking, check.Requirement)
+				}
+			}
+
+			assert.Equal(t, tt.wantBlocking, blocking)
+
+			reached := Stub
+			for _, step := range explanation.Steps {
+				if step.Reached() {
+					reached = step.State
+				}
+			}
+
+			assert.Equal(t, tt.body.CalculateState(), reached)
+		})
+	}
+}
+
+func TestChecker_ExplainState(t *testing.T) {
+	checker := NewChecker(WithRoot("site"), WithFS(checkerTestFS()))
+
+	t.Run("index page", func(t *testing.T) {
+		// execute
+		got, err := checker.ExplainState("site/content/a1/go/_index.md")
+
+		// verify
+		require.NoError(t, err)
+		assert.Equal(t, IndexPage, got.Kind)
+		assert.Equal(t, Incomplete, got.Calculated)
+		assert.Equal(t, []StateInput{
+			{Name: "episodes section", Value: "yes", Satisfied: true},
+			{Name: "complete pages in chapter", Value: "0/3", Satisfied: false},
+		}, got.Inputs)
+		assert.Equal(t, []string{"complete the pages: site/content/a1/go/10-foo.md, site/content/a1/go/20-bar.md, site/content/a1/go/30-qux.tmp.md"}, got.ToComplete())
+	})
+
+	t.Run("unknown page", func(t *testing.T) {
+		// execute
+		_, err := checker.ExplainState("site/content/a1/go/40-nope.md")
+
+		// verify
+		require.ErrorContains(t, err, "page not found: site/content/a1/go/40-nope.md")
+	})
+}
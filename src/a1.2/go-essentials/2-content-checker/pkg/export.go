@@ -0,0 +1,502 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CatalogSchemaVersion is increased on every change of the exported fields which is not backwards compatible, added
+// fields do not change the version
+const CatalogSchemaVersion = 1
+
+type PageKind string
+
+const (
+	DefaultPage  PageKind = "page"
+	IndexPage    PageKind = "index"
+	PracticePage PageKind = "practice"
+)
+
+// Catalog is the exported form of the course model, see CourseExport, ChapterExport and PageExport for the fields.
+// Optional fields are omitted if empty, lists are always present.
+type Catalog struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Courses       []CourseExport `json:"courses"`
+}
+
+type CourseExport struct {
+	// Title is the name of the course directory
+	Title string `json:"title"`
+	// State is calculated from the states of the chapters
+	State State `json:"state"`
+	// Index is the course index page, it is omitted if missing
+	Index *PageExport `json:"index,omitempty"`
+	// Translations contains the translations of the course index page
+	Translations []PageExport `json:"translations"`
+	// Issues contains the problems of the course, including the ones of its index page
+	Issues   []string        `json:"issues"`
+	Chapters []ChapterExport `json:"chapters"`
+}
+
+type ChapterExport struct {
+	// Title is the name of the chapter directory
+	Title string `json:"title"`
+	// State is calculated from the states of the non-index pages
+	State State        `json:"state"`
+	Pages []PageExport `json:"pages"`
+}
+
+type PageExport struct {
+	FilePath string `json:"filePath"`
+	// FileName is the file name of the page as found while crawling
+	FileName string `json:"fileName"`
+	// Language is the language code of translations, omitted for pages in the default language
+	Language          string     `json:"language,omitempty"`
+	Kind              PageKind   `json:"kind"`
+	Title             string     `json:"title"`
+	Description       string     `json:"description,omitempty"`
+	Slug              string     `json:"slug"`
+	Weight            string     `json:"weight"`
+	State             State      `json:"state"`
+	CalculatedState   State      `json:"calculatedState"`
+	Audience          Audience   `json:"audience"`
+	Importance        Importance `json:"importance"`
+	OutsideImportance Importance `json:"outsideImportance,omitempty"`
+	Tags              []string   `json:"tags"`
+	Prerequisites     []string   `json:"prerequisites"`
+	LastReviewed      string     `json:"lastReviewed,omitempty"`
+	SectionTitles     []string   `json:"sectionTitles"`
+	Body              BodyExport `json:"body"`
+	// Issues contains every problem of the page, just like the errors command
+	Issues       []string     `json:"issues"`
+	Translations []PageExport `json:"translations,omitempty"`
+}
+
+// BodyExport contains the fields of the body of a page, only the fields of its kind are present
+type BodyExport struct {
+	// page
+	MainVideo          MainVideo            `json:"mainVideo,omitempty"`
+	MainVideoProblem   *MainVideoProblem    `json:"mainVideoProblem,omitempty"`
+	HasSummary         bool                 `json:"hasSummary,omitempty"`
+	HasTopics          bool                 `json:"hasTopics,omitempty"`
+	HasExercises       bool                 `json:"hasExercises,omitempty"`
+	HasRelatedLinks    bool                 `json:"hasRelatedLinks,omitempty"`
+	UsefulWithoutVideo bool                 `json:"usefulWithoutVideo,omitempty"`
+	SlugForced         bool                 `json:"slugForced,omitempty"`
+	Project            bool                 `json:"project,omitempty"`
+	RelatedVideos      []RelatedVideoExport `json:"relatedVideos,omitempty"`
+
+	// index
+	HasEpisodes   bool            `json:"hasEpisodes,omitempty"`
+	Episodes      []EpisodeExport `json:"episodes,omitempty"`
+	CompleteState State           `json:"completeState,omitempty"`
+
+	// practice
+	HasDescription           bool              `json:"hasDescription,omitempty"`
+	HasRecommendedChallenges bool              `json:"hasRecommendedChallenges,omitempty"`
+	HasAdditionalChallenges  bool              `json:"hasAdditionalChallenges,omitempty"`
+	RecommendedChallenges    []ChallengeExport `json:"recommendedChallenges,omitempty"`
+	AdditionalChallenges     []ChallengeExport `json:"additionalChallenges,omitempty"`
+}
+
+type RelatedVideoExport struct {
+	Badge   Badge    `json:"badge,omitempty"`
+	Minutes int      `json:"minutes"`
+	Valid   bool     `json:"valid"`
+	Issues  []string `json:"issues,omitempty"`
+}
+
+type EpisodeExport struct {
+	Title string `json:"title"`
+	Link  string `json:"link,omitempty"`
+}
+
+type ChallengeExport struct {
+	Title      string     `json:"title"`
+	Link       string     `json:"link,omitempty"`
+	Difficulty Difficulty `json:"difficulty,omitempty"`
+	Issues     []string   `json:"issues,omitempty"`
+}
+
+// Export returns the catalog of the courses, the courses are expected to be prepared
+func (c Courses) Export() Catalog {
+	catalog := Catalog{SchemaVersion: CatalogSchemaVersion, Courses: make([]CourseExport, 0, len(c))}
+
+	for _, course := range c {
+		courseExport := CourseExport{
+			Title:        course.Title,
+			State:        course.CalculateState(),
+			Translations: make([]PageExport, 0, len(course.Translations)),
+			Issues:       nonNil(course.GetIssues()),
+			Chapters:     make([]ChapterExport, 0, len(course.Chapters)),
+		}
+
+		// course index pages are validated by their courses, only their own issues are exported
+		if course.Index != nil {
+			index := exportPage(*course.Index, course.Index.Issues)
+			courseExport.Index = &index
+		}
+
+		for _, translation := range course.Translations {
+			courseExport.Translations = append(courseExport.Translations, exportPage(translation, translation.Issues))
+		}
+
+		for _, chapter := range course.Chapters {
+			chapterExport := ChapterExport{Title: chapter.Title, State: chapter.CalculateState(), Pages: make([]PageExport, 0, len(chapter.Pages))}
+
+			for _, page := range chapter.Pages {
+				pageExport := exportPage(page, page.GetIssues())
+
+				for _, translation := range page.Translations {
+					pageExport.Translations = append(pageExport.Translations, exportPage(translation, translation.GetIssues()))
+				}
+
+				chapterExport.Pages = append(chapterExport.Pages, pageExport)
+			}
+
+			courseExport.Chapters = append(courseExport.Chapters, chapterExport)
+		}
+
+		catalog.Courses = append(catalog.Courses, courseExport)
+	}
+
+	return catalog
+}
+
+func exportPage(page Page, issues []string) PageExport {
+	content := page.Content
+
+	export := PageExport{
+		FilePath:          page.FilePath,
+		FileName:          page.Title,
+		Language:          page.Language,
+		Title:             content.Title,
+		Description:       content.Description,
+		Slug:              content.Slug,
+		Weight:            content.Weight,
+		State:             content.State,
+		Audience:          content.Audience,
+		Importance:        content.Importance,
+		OutsideImportance: content.OutsideImportance,
+		Tags:              nonNil(content.Tags),
+		Prerequisites:     nonNil(content.Prerequisites),
+		LastReviewed:      content.LastReviewed,
+		SectionTitles:     []string{},
+		Issues:            nonNil(issues),
+	}
+
+	if content.Body != nil {
+		export.CalculatedState = content.Body.CalculateState()
+	}
+
+	switch body := content.Body.(type) {
+	case DefaultBody:
+		export.Kind = DefaultPage
+		export.SectionTitles = nonNil(body.SectionTitles)
+		export.Body = BodyExport{
+			MainVideo:          body.MainVideo,
+			MainVideoProblem:   body.MainVideoProblem,
+			HasSummary:         body.HasSummary,
+			HasTopics:          body.HasTopics,
+			HasExercises:       body.HasExercises,
+			HasRelatedLinks:    body.HasRelatedLinks,
+			UsefulWithoutVideo: body.UsefulWithoutVideo,
+			SlugForced:         body.SlugForced,
+			Project:            body.Project,
+		}
+
+		for _, video := range body.RelatedVideos {
+			export.Body.RelatedVideos = append(export.Body.RelatedVideos, RelatedVideoExport{Badge: video.Badge, Minutes: video.Minutes, Valid: video.Valid, Issues: video.Issues})
+		}
+	case *IndexBody:
+		export.Kind = IndexPage
+		export.Body = BodyExport{HasEpisodes: body.HasEpisodes, CompleteState: body.CompleteState}
+
+		for _, episode := range body.Episodes {
+			export.Body.Episodes = append(export.Body.Episodes, EpisodeExport{Title: episode.Title, Link: episode.Link})
+		}
+	case *PracticeBody:
+		export.Kind = PracticePage
+		export.SectionTitles = nonNil(body.SectionTitles)
+		export.Body = BodyExport{
+			HasDescription:           body.HasDescription,
+			HasRecommendedChallenges: body.HasRecommendedChallenges,
+			HasAdditionalChallenges:  body.HasAdditionalChallenges,
+			RecommendedChallenges:    exportChallenges(body.RecommendedChallenges),
+			AdditionalChallenges:     exportChallenges(body.AdditionalChallenges),
+		}
+	}
+
+	return export
+}
+
+func exportChallenges(challenges Challenges) []ChallengeExport {
+	var result []ChallengeExport
+
+	for _, challenge := range challenges {
+		result = append(result, ChallengeExport{Title: challenge.Title, Link: challenge.Link, Difficulty: challenge.Difficulty, Issues: challenge.Issues})
+	}
+
+	return result
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
+
+func (c Catalog) JSON() (string, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + EOL, nil
+}
+
+// CSV returns one row for every page and translation, list fields are joined by semicolons and related videos are
+// listed as badge:minutes pairs
+func (c Catalog) CSV() (string, error) {
+	buf := &bytes.Buffer{}
+
+	w := csv.NewWriter(buf)
+
+	records := [][]string{{
+		"course", "chapter", "filePath", "language", "kind", "title", "slug", "weight", "state", "calculatedState",
+		"audience", "importance", "outsideImportance", "tags", "prerequisites", "sectionTitles", "mainVideo",
+		"relatedVideos", "issues",
+	}}
+
+	addPage := func(course, chapter string, page PageExport) {
+		relatedVideos := make([]string, 0, len(page.Body.RelatedVideos))
+		for _, video := range page.Body.RelatedVideos {
+			relatedVideos = append(relatedVideos, fmt.Sprintf("%s:%d", video.Badge, video.Minutes))
+		}
+
+		records = append(records, []string{
+			course,
+			chapter,
+			page.FilePath,
+			page.Language,
+			string(page.Kind),
+			page.Title,
+			page.Slug,
+			page.Weight,
+			string(page.State),
+			string(page.CalculatedState),
+			string(page.Audience),
+			string(page.Importance),
+			string(page.OutsideImportance),
+			strings.Join(page.Tags, ";"),
+			strings.Join(page.Prerequisites, ";"),
+			strings.Join(page.SectionTitles, ";"),
+			string(page.Body.MainVideo),
+			strings.Join(relatedVideos, ";"),
+			strings.Join(page.Issues, ";"),
+		})
+	}
+
+	for _, course := range c.Courses {
+		if course.Index != nil {
+			addPage(course.Title, "", *course.Index)
+		}
+
+		for _, translation := range course.Translations {
+			addPage(course.Title, "", translation)
+		}
+
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				addPage(course.Title, chapter.Title, page)
+
+				for _, translation := range page.Translations {
+					addPage(course.Title, chapter.Title, translation)
+				}
+			}
+		}
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// LoadCatalog reads an exported JSON catalog back into the course model. The loaded courses are prepared already and
+// report the same issues as the exported ones.
+func LoadCatalog(raw []byte) (Courses, error) {
+	var catalog Catalog
+
+	if err := json.Unmarshal(raw, &catalog); err != nil {
+		return nil, fmt.Errorf("catalog could not be parsed, err: %w", err)
+	}
+
+	if catalog.SchemaVersion != CatalogSchemaVersion {
+		return nil, fmt.Errorf("unsupported catalog schema version: %d, want: %d", catalog.SchemaVersion, CatalogSchemaVersion)
+	}
+
+	courses := make(Courses, 0, len(catalog.Courses))
+
+	for _, courseExport := range catalog.Courses {
+		course := Course{Title: courseExport.Title, prepared: true}
+
+		if courseExport.Index != nil {
+			index, err := loadPage(*courseExport.Index, false)
+			if err != nil {
+				return nil, err
+			}
+
+			course.Index = &index
+		}
+
+		for _, translationExport := range courseExport.Translations {
+			translation, err := loadPage(translationExport, false)
+			if err != nil {
+				return nil, err
+			}
+
+			course.Translations = append(course.Translations, translation)
+		}
+
+		for _, chapterExport := range courseExport.Chapters {
+			chapter := &Chapter{Title: chapterExport.Title, prepared: true}
+
+			for _, pageExport := range chapterExport.Pages {
+				page, err := loadPage(pageExport, true)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, translationExport := range pageExport.Translations {
+					translation, err := loadPage(translationExport, true)
+					if err != nil {
+						return nil, err
+					}
+
+					page.Translations = append(page.Translations, translation)
+				}
+
+				chapter.Pages = append(chapter.Pages, page)
+			}
+
+			course.Chapters = append(course.Chapters, chapter)
+		}
+
+		courses = append(courses, course)
+	}
+
+	return courses, nil
+}
+
+// loadPage creates a page from its export. The issues of the content are calculated again, if withContentIssues is set
+// only the remaining ones are kept as the issues of the page.
+func loadPage(export PageExport, withContentIssues bool) (Page, error) {
+	content := Content{
+		Title:             export.Title,
+		Description:       export.Description,
+		State:             export.State,
+		Slug:              export.Slug,
+		Weight:            export.Weight,
+		Audience:          export.Audience,
+		Importance:        export.Importance,
+		OutsideImportance: export.OutsideImportance,
+		Tags:              export.Tags,
+		Prerequisites:     export.Prerequisites,
+		LastReviewed:      export.LastReviewed,
+	}
+
+	body := export.Body
+
+	switch export.Kind {
+	case DefaultPage:
+		defaultBody := DefaultBody{
+			MainVideo:          body.MainVideo,
+			MainVideoProblem:   body.MainVideoProblem,
+			HasSummary:         body.HasSummary,
+			HasTopics:          body.HasTopics,
+			HasExercises:       body.HasExercises,
+			HasRelatedLinks:    body.HasRelatedLinks,
+			UsefulWithoutVideo: body.UsefulWithoutVideo,
+			SlugForced:         body.SlugForced,
+			Project:            body.Project,
+			SectionTitles:      export.SectionTitles,
+		}
+
+		for _, video := range body.RelatedVideos {
+			defaultBody.RelatedVideos = append(defaultBody.RelatedVideos, RelatedVideo{Badge: video.Badge, Minutes: video.Minutes, Valid: video.Valid, Issues: video.Issues})
+		}
+
+		content.Body = defaultBody
+	case IndexPage:
+		indexBody := &IndexBody{HasEpisodes: body.HasEpisodes, CompleteState: body.CompleteState}
+
+		for _, episode := range body.Episodes {
+			indexBody.Episodes = append(indexBody.Episodes, Episode{Title: episode.Title, Link: episode.Link})
+		}
+
+		content.Body = indexBody
+	case PracticePage:
+		content.Body = &PracticeBody{
+			HasDescription:           body.HasDescription,
+			HasRecommendedChallenges: body.HasRecommendedChallenges,
+			HasAdditionalChallenges:  body.HasAdditionalChallenges,
+			RecommendedChallenges:    loadChallenges(body.RecommendedChallenges),
+			AdditionalChallenges:     loadChallenges(body.AdditionalChallenges),
+			SectionTitles:            export.SectionTitles,
+		}
+	default:
+		return Page{}, fmt.Errorf("unknown page kind: %s, page: %s", export.Kind, export.FilePath)
+	}
+
+	page := Page{FilePath: export.FilePath, Title: export.FileName, Content: content, Language: export.Language}
+
+	issues := export.Issues
+	if withContentIssues {
+		issues = subtractIssues(issues, content.GetIssues(export.FilePath))
+	}
+
+	if len(issues) > 0 {
+		page.Issues = issues
+	}
+
+	return page, nil
+}
+
+func loadChallenges(exports []ChallengeExport) Challenges {
+	var challenges Challenges
+
+	for _, export := range exports {
+		challenges = append(challenges, Challenge{Title: export.Title, Link: export.Link, Difficulty: export.Difficulty, Issues: export.Issues})
+	}
+
+	return challenges
+}
+
+// subtractIssues removes the first occurrence of every issue to remove from issues
+func subtractIssues(issues, remove []string) []string {
+	counts := make(map[string]int, len(remove))
+	for _, issue := range remove {
+		counts[issue]++
+	}
+
+	var result []string
+
+	for _, issue := range issues {
+		if counts[issue] > 0 {
+			counts[issue]--
+
+			continue
+		}
+
+		result = append(result, issue)
+	}
+
+	return result
+}
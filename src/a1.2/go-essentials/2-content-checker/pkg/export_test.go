@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestCourses(t *testing.T) Courses {
+	t.Helper()
+
+	courses := testCourses(
+		parseTestPage(t, "content/a1/go/_index.md",
+			"+++\ntitle = 'Go'\nweight = 10\naudience = \"all\"\n+++\n\n## Episodes\n\n- [Foo]({{< ref \"10-foo.md\" >}})\n"),
+		parseTestPage(t, "content/a1/go/10-foo.md",
+			"+++\ntitle = 'Foo'\nslug = 'foo'\nweight = 10\nstate = 'incomplete'\naudience = \"all\"\naudienceImportance = \"important\"\ntags = [\"go\"]\n+++\n\n"+
+				"## Main Video\n\n{{< youtube abc >}}\n\n## Summary\n\n- foo\n\n## Topics\n\n- bar\n\n"+
+				"## Related Videos\n\n{{< badge-extra >}}\n{{< time 4 >}}\n{{< youtube def >}}\n\n```\nfoo\n```\n"),
+		parseTestPage(t, "content/a1/go/10-foo.pl.md", "+++\ntitle = 'Fu'\nslug = 'fu'\nweight = 10\naudience = \"all\"\n+++\n"),
+	)
+
+	courses = courses.AddIndex("content/a1/_index.md", "a1", Content{Title: "A1", Description: "Basics", Audience: All, Body: DefaultBody{}})
+
+	for i := range courses {
+		courses[i].Prepare()
+	}
+
+	courses.CheckTranslations(courses.Languages(), nil)
+
+	return courses
+}
+
+func TestCourses_Export(t *testing.T) {
+	courses := exportTestCourses(t)
+
+	// execute
+	got := courses.Export()
+
+	// verify
+	require.Len(t, got.Courses, 1)
+	assert.Equal(t, CatalogSchemaVersion, got.SchemaVersion)
+
+	course := got.Courses[0]
+	assert.Equal(t, "a1", course.Title)
+	require.NotNil(t, course.Index)
+	assert.Equal(t, "Basics", course.Index.Description)
+	require.Len(t, course.Chapters, 1)
+
+	pages := course.Chapters[0].Pages
+	require.Len(t, pages, 2)
+	assert.Equal(t, IndexPage, pages[0].Kind)
+	assert.Equal(t, []EpisodeExport{{Title: "Foo", Link: `{{< ref "10-foo.md" >}}`}}, pages[0].Body.Episodes)
+
+	page := pages[1]
+	assert.Equal(t, DefaultPage, page.Kind)
+	assert.Equal(t, Incomplete, page.CalculatedState)
+	assert.Equal(t, []string{"go"}, page.Tags)
+	assert.Equal(t, []string{sectionMainVideo, sectionSummary, sectionTopics, sectionRelatedVideos}, page.SectionTitles)
+	assert.Equal(t, []RelatedVideoExport{{Badge: Extra, Minutes: 4, Valid: true, Issues: []string{"badge should be placed after time"}}}, page.Body.RelatedVideos)
+	assert.Equal(t, []string{"badge should be placed after time", "line 29: code block without a language tag"}, page.Issues)
+	require.Len(t, page.Translations, 1)
+	assert.Equal(t, "pl", page.Translations[0].Language)
+	assert.Contains(t, page.Translations[0].Issues, "translation slug differs from the source: fu, want: foo")
+}
+
+func TestCatalog_CSV(t *testing.T) {
+	catalog := Catalog{SchemaVersion: CatalogSchemaVersion, Courses: []CourseExport{{
+		Title: "a1",
+		Chapters: []ChapterExport{{Title: "go", Pages: []PageExport{{
+			FilePath: "content/a1/go/10-foo.md",
+			Kind:     DefaultPage,
+			Title:    "Foo, the first",
+			Tags:     []string{"go", "cli"},
+			Body:     BodyExport{MainVideo: VideoPresent, RelatedVideos: []RelatedVideoExport{{Badge: Extra, Minutes: 4}}},
+			Issues:   []string{"summary section is missing"},
+		}}}},
+	}}}
+
+	// execute
+	got, err := catalog.CSV()
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "course,chapter,filePath,language,kind,title,slug,weight,state,calculatedState,audience,importance,outsideImportance,tags,prerequisites,sectionTitles,mainVideo,relatedVideos,issues\n"+
+		"a1,go,content/a1/go/10-foo.md,,page,\"Foo, the first\",,,,,,,,go;cli,,,present,extra:4,summary section is missing\n", got)
+}
+
+func TestLoadCatalog(t *testing.T) {
+	courses := exportTestCourses(t)
+
+	raw, err := courses.Export().JSON()
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		// execute
+		got, err := LoadCatalog([]byte(raw))
+
+		// verify
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, courses[0].GetErrors(), got[0].GetErrors())
+		assert.Equal(t, courses.Export(), got.Export())
+	})
+
+	t.Run("unsupported schema version", func(t *testing.T) {
+		// execute
+		_, err := LoadCatalog([]byte(`{"schemaVersion": 2, "courses": []}`))
+
+		// verify
+		require.Error(t, err)
+	})
+
+	t.Run("unknown page kind", func(t *testing.T) {
+		// execute
+		_, err := LoadCatalog([]byte(`{"schemaVersion": 1, "courses": [{"title": "a1", "chapters": [{"title": "go", "pages": [{"kind": "foo"}]}]}]}`))
+
+		// verify
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,169 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMaxReviewAges is used for the importance levels missing from the config, in days
+var DefaultMaxReviewAges = map[Importance]int{
+	Critical:  180,
+	Essential: 180,
+	Important: 365,
+	Relevant:  365,
+	Optional:  730,
+}
+
+// ReviewDate returns the last reviewed date of the front matter, the zero time is returned if it is missing. Besides
+// dates, TOML date-times are accepted too.
+func (c Content) ReviewDate() (time.Time, error) {
+	if c.LastReviewed == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err := time.Parse(time.DateOnly, c.LastReviewed); err == nil {
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, c.LastReviewed)
+}
+
+// MaxReviewAge returns the number of days a page of the given importance may go without a review
+func (c Config) MaxReviewAge(importance Importance) (int, bool) {
+	if days, ok := c.MaxReviewAges[importance]; ok {
+		return days, true
+	}
+
+	days, ok := DefaultMaxReviewAges[importance]
+
+	return days, ok
+}
+
+type ReviewSource string
+
+const (
+	ReviewedFrontMatter ReviewSource = "front matter"
+	ReviewedCommit      ReviewSource = "git"
+	ReviewedNever       ReviewSource = "never"
+)
+
+type StalePage struct {
+	FilePath   string     `json:"filePath"`
+	Title      string     `json:"title"`
+	Importance Importance `json:"importance"`
+	// Reviewed is the date of the last review, empty if the page was never reviewed
+	Reviewed string       `json:"reviewed,omitempty"`
+	Source   ReviewSource `json:"source"`
+	// Age is the number of days since the last review, -1 if the page was never reviewed
+	Age    int `json:"age"`
+	MaxAge int `json:"maxAge"`
+}
+
+type StalePages []StalePage
+
+const day = 24 * time.Hour
+
+// StalePages returns the pages which were not reviewed within the maximum age of their importance, the most important
+// and oldest pages first. The last reviewed date of the front matter is used if present, the time of the last commit of
+// the page otherwise. Index pages, translations and pages without a valid importance are not listed.
+func (c Courses) StalePages(config Config, commitTimes map[string]time.Time, now time.Time) StalePages {
+	var stale StalePages
+
+	for _, course := range c {
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				if page.IsIndex() || page.Language != "" {
+					continue
+				}
+
+				maxAge, ok := config.MaxReviewAge(page.Content.Importance)
+				if !ok {
+					continue
+				}
+
+				stalePage := StalePage{
+					FilePath:   page.FilePath,
+					Title:      page.Content.Title,
+					Importance: page.Content.Importance,
+					Source:     ReviewedNever,
+					Age:        -1,
+					MaxAge:     maxAge,
+				}
+
+				var reviewed time.Time
+
+				if date, err := page.Content.ReviewDate(); err == nil && !date.IsZero() {
+					reviewed, stalePage.Source = date, ReviewedFrontMatter
+				} else if committed, ok := commitTimes[page.FilePath]; ok {
+					reviewed, stalePage.Source = committed, ReviewedCommit
+				}
+
+				if stalePage.Source != ReviewedNever {
+					stalePage.Reviewed = reviewed.Format(time.DateOnly)
+					stalePage.Age = int(now.Sub(reviewed) / day)
+
+					if stalePage.Age <= maxAge {
+						continue
+					}
+				}
+
+				stale = append(stale, stalePage)
+			}
+		}
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		a, b := stale[i], stale[j]
+
+		if a.Importance.Level() != b.Importance.Level() {
+			return a.Importance.Level() > b.Importance.Level()
+		}
+
+		if (a.Age < 0) != (b.Age < 0) {
+			return a.Age < 0
+		}
+
+		return a.Age > b.Age
+	})
+
+	return stale
+}
+
+func (sp StalePages) String() string {
+	width := len("Page")
+	for _, page := range sp {
+		width = max(width, len(page.FilePath))
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s | %s | %s | %s | %s\n", column("Page", width, cliBold), column("Importance", 10, cliBold), column("Reviewed", 10, cliBold), column("Source", 12, cliBold), column("Age", 9, cliBold)))
+	sb.WriteString(strings.Repeat("-", width+1) + "+" + strings.Repeat("-", 12) + "+" + strings.Repeat("-", 12) + "+" + strings.Repeat("-", 14) + "+" + strings.Repeat("-", 10) + EOL)
+
+	for _, page := range sp {
+		reviewed, age := "-", "-"
+		if page.Source != ReviewedNever {
+			reviewed, age = page.Reviewed, fmt.Sprintf("%d/%d", page.Age, page.MaxAge)
+		}
+
+		sb.WriteString(fmt.Sprintf("%s | %s | %s | %s | %s\n", column(page.FilePath, width, cliReset), column(page.Importance, 10, cliReset), column(reviewed, 10, cliReset), column(page.Source, 12, cliReset), column(age, 9, cliRed)))
+	}
+
+	return sb.String()
+}
+
+func (sp StalePages) JSON() (string, error) {
+	if sp == nil {
+		sp = StalePages{}
+	}
+
+	raw, err := json.MarshalIndent(sp, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw) + EOL, nil
+}
@@ -0,0 +1,101 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContent_ReviewDate(t *testing.T) {
+	tests := []struct {
+		name         string
+		lastReviewed string
+		want         time.Time
+		wantErr      bool
+	}{
+		{
+			name: "missing",
+			want: time.Time{},
+		},
+		{
+			name:         "date",
+			lastReviewed: "2024-05-01",
+			want:         time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "date-time",
+			lastReviewed: "2024-05-01T10:30:00Z",
+			want:         time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "invalid",
+			lastReviewed: "01/05/2024",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got, err := Content{LastReviewed: tt.lastReviewed}.ReviewDate()
+
+			// verify
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCourses_StalePages(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	page := func(filePath string, importance Importance, lastReviewed string) Page {
+		return Page{FilePath: filePath, Content: Content{Title: filePath, Importance: importance, LastReviewed: lastReviewed, Body: DefaultBody{}}}
+	}
+
+	courses := Courses{{Title: "a1", Chapters: Chapters{{Title: "go", Pages: Pages{
+		{FilePath: "content/a1/go/_index.md", Title: "_index.md", Content: Content{Importance: Critical, Body: &IndexBody{}}},
+		page("content/a1/go/10-fresh.md", Critical, "2024-05-01"),
+		page("content/a1/go/20-old.md", Important, "2023-01-01"),
+		page("content/a1/go/30-committed.md", Critical, ""),
+		page("content/a1/go/40-never.md", Important, ""),
+		page("content/a1/go/50-older.md", Important, "2022-01-01"),
+		page("content/a1/go/60-unknown.md", "", "2020-01-01"),
+		page("content/a1/go/70-configured.md", Optional, "2024-01-01"),
+	}}}}}
+
+	config := Config{MaxReviewAges: map[Importance]int{Optional: 30}}
+	commitTimes := map[string]time.Time{
+		"content/a1/go/30-committed.md": time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+		"content/a1/go/20-old.md":       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// execute
+	got := courses.StalePages(config, commitTimes, now)
+
+	// verify
+	assert.Equal(t, StalePages{
+		{FilePath: "content/a1/go/30-committed.md", Title: "content/a1/go/30-committed.md", Importance: Critical, Reviewed: "2023-06-01", Source: ReviewedCommit, Age: 365, MaxAge: 180},
+		{FilePath: "content/a1/go/40-never.md", Title: "content/a1/go/40-never.md", Importance: Important, Source: ReviewedNever, Age: -1, MaxAge: 365},
+		{FilePath: "content/a1/go/50-older.md", Title: "content/a1/go/50-older.md", Importance: Important, Reviewed: "2022-01-01", Source: ReviewedFrontMatter, Age: 882, MaxAge: 365},
+		{FilePath: "content/a1/go/20-old.md", Title: "content/a1/go/20-old.md", Importance: Important, Reviewed: "2023-01-01", Source: ReviewedFrontMatter, Age: 517, MaxAge: 365},
+		{FilePath: "content/a1/go/70-configured.md", Title: "content/a1/go/70-configured.md", Importance: Optional, Reviewed: "2024-01-01", Source: ReviewedFrontMatter, Age: 152, MaxAge: 30},
+	}, got)
+}
+
+func TestContent_GetIssues_LastReviewed(t *testing.T) {
+	// execute
+	valid := Content{LastReviewed: "2024-05-01", Body: DefaultBody{}}.GetIssues("foo.md")
+	invalid := Content{LastReviewed: "May 2024", Body: DefaultBody{}}.GetIssues("foo.md")
+
+	// verify
+	assert.NotContains(t, valid, "last reviewed date is invalid: 2024-05-01")
+	assert.Contains(t, invalid, "last reviewed date is invalid: May 2024")
+}
@@ -0,0 +1,594 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// SiteAnalyzer crawls and checks the whole site. documents contains the content of the files open in the editor keyed
+// by their paths, it replaces the content found on the disk.
+type SiteAnalyzer func(documents map[string]string) (Courses, error)
+
+// LanguageServer speaks the Language Server Protocol, publishing the issues of the open markdown files as diagnostics,
+// offering fixes for some of them and completing badges, audiences, importance levels, states and tags. Only full
+// document synchronization is supported.
+type LanguageServer struct {
+	in      *bufio.Reader
+	out     io.Writer
+	analyze SiteAnalyzer
+	// tags are offered for completion, the tags used by the site are offered if empty
+	tags []string
+
+	documents map[string]string
+	courses   Courses
+	shutdown  bool
+}
+
+func NewLanguageServer(in io.Reader, out io.Writer, analyze SiteAnalyzer, tags []string) *LanguageServer {
+	return &LanguageServer{
+		in:        bufio.NewReader(in),
+		out:       out,
+		analyze:   analyze,
+		tags:      tags,
+		documents: make(map[string]string),
+	}
+}
+
+const (
+	lspParseError     = -32700
+	lspMethodNotFound = -32601
+	lspInvalidParams  = -32602
+
+	lspSeverityError = 1
+
+	lspTextDocumentSyncFull = 1
+
+	lspMessageError = 1
+
+	lspCompletionValue = 12
+)
+
+type lspMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type lspResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+}
+
+type lspErrorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   lspError        `json:"error"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspTextDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text,omitempty"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspRenameFile struct {
+	Kind   string `json:"kind"`
+	OldURI string `json:"oldUri"`
+	NewURI string `json:"newUri"`
+}
+
+type lspWorkspaceEdit struct {
+	Changes         map[string][]lspTextEdit `json:"changes,omitempty"`
+	DocumentChanges []lspRenameFile          `json:"documentChanges,omitempty"`
+}
+
+type lspCodeAction struct {
+	Title       string           `json:"title"`
+	Kind        string           `json:"kind"`
+	Diagnostics []lspDiagnostic  `json:"diagnostics"`
+	Edit        lspWorkspaceEdit `json:"edit"`
+}
+
+type lspCompletionItem struct {
+	Label string `json:"label"`
+	Kind  int    `json:"kind"`
+}
+
+// Serve handles the messages of the client until the exit notification or the end of the input
+func (s *LanguageServer) Serve() error {
+	for {
+		message, err := s.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if message.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit notification received before shutdown")
+			}
+
+			return nil
+		}
+
+		if err := s.handle(message); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *LanguageServer) read() (lspMessage, error) {
+	header, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		return lspMessage{}, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return lspMessage{}, fmt.Errorf("invalid content length: %s", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return lspMessage{}, err
+	}
+
+	var message lspMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return lspMessage{}, s.write(lspErrorResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: lspError{Code: lspParseError, Message: err.Error()}})
+	}
+
+	return message, nil
+}
+
+func (s *LanguageServer) write(message any) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+
+	return err
+}
+
+func (s *LanguageServer) respond(id json.RawMessage, result any) error {
+	return s.write(lspResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *LanguageServer) respondError(id json.RawMessage, code int, message string) error {
+	return s.write(lspErrorResponse{JSONRPC: "2.0", ID: id, Error: lspError{Code: code, Message: message}})
+}
+
+func (s *LanguageServer) notify(method string, params any) error {
+	return s.write(lspNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *LanguageServer) handle(message lspMessage) error {
+	var params struct {
+		TextDocument   lspTextDocument `json:"textDocument"`
+		ContentChanges []struct {
+			Text string `json:"text"`
+		} `json:"contentChanges"`
+		Position lspPosition `json:"position"`
+		Context  struct {
+			Diagnostics []lspDiagnostic `json:"diagnostics"`
+		} `json:"context"`
+	}
+
+	if len(message.Params) > 0 {
+		if err := json.Unmarshal(message.Params, &params); err != nil {
+			if message.ID == nil {
+				return nil
+			}
+
+			return s.respondError(message.ID, lspInvalidParams, err.Error())
+		}
+	}
+
+	filePath := uriToPath(params.TextDocument.URI)
+
+	switch message.Method {
+	case "initialize":
+		return s.respond(message.ID, map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    lspTextDocumentSyncFull,
+					"save":      true,
+				},
+				"codeActionProvider": true,
+				"completionProvider": map[string]any{
+					"triggerCharacters": []string{`"`, "'", "-", "[", " "},
+				},
+			},
+			"serverInfo": map[string]string{"name": "mdcheck"},
+		})
+
+	case "shutdown":
+		s.shutdown = true
+
+		return s.respond(message.ID, nil)
+
+	case "textDocument/didOpen":
+		s.documents[filePath] = params.TextDocument.Text
+
+		return s.publish()
+
+	case "textDocument/didChange":
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+
+		s.documents[filePath] = params.ContentChanges[len(params.ContentChanges)-1].Text
+
+		return s.publish()
+
+	case "textDocument/didSave":
+		return s.publish()
+
+	case "textDocument/didClose":
+		delete(s.documents, filePath)
+
+		if err := s.notify("textDocument/publishDiagnostics", map[string]any{"uri": params.TextDocument.URI, "diagnostics": []lspDiagnostic{}}); err != nil {
+			return err
+		}
+
+		return s.publish()
+
+	case "textDocument/codeAction":
+		return s.respond(message.ID, codeActions(params.TextDocument.URI, s.documents[filePath], params.Context.Diagnostics))
+
+	case "textDocument/completion":
+		return s.respond(message.ID, s.complete(s.documents[filePath], params.Position))
+	}
+
+	// unknown notifications are ignored
+	if message.ID == nil {
+		return nil
+	}
+
+	return s.respondError(message.ID, lspMethodNotFound, "method not found: "+message.Method)
+}
+
+// publish checks the site again and publishes the diagnostics of every open document. Documents which can not be
+// parsed are reported as such and their content on the disk is checked instead.
+func (s *LanguageServer) publish() error {
+	documents := make(map[string]string, len(s.documents))
+	parseErrors := make(map[string]string)
+
+	for filePath, text := range s.documents {
+		if _, err := ParseMarkdown(text); err != nil {
+			parseErrors[filePath] = err.Error()
+
+			continue
+		}
+
+		documents[filePath] = text
+	}
+
+	courses, err := s.analyze(documents)
+	if err != nil {
+		return s.notify("window/showMessage", map[string]any{"type": lspMessageError, "message": "mdcheck: " + err.Error()})
+	}
+
+	s.courses = courses
+
+	filePaths := make([]string, 0, len(s.documents))
+	for filePath := range s.documents {
+		filePaths = append(filePaths, filePath)
+	}
+
+	sort.Strings(filePaths)
+
+	for _, filePath := range filePaths {
+		text := s.documents[filePath]
+
+		diagnostics := []lspDiagnostic{}
+
+		issues := courses.issuesOf(filePath)
+		if parseError, ok := parseErrors[filePath]; ok {
+			issues = []string{parseError}
+		}
+
+		for _, issue := range issues {
+			diagnostics = append(diagnostics, lspDiagnostic{Range: issueRange(text, issue), Severity: lspSeverityError, Source: "mdcheck", Message: issue})
+		}
+
+		if err := s.notify("textDocument/publishDiagnostics", map[string]any{"uri": pathToURI(filePath), "diagnostics": diagnostics}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// issuesOf returns the issues of the page with the given path, the same ones as reported by the errors command
+func (c Courses) issuesOf(filePath string) []string {
+	for _, course := range c {
+		if course.Index != nil && course.Index.FilePath == filePath {
+			return course.GetIssues()
+		}
+
+		for _, translation := range course.Translations {
+			if translation.FilePath == filePath {
+				return translation.Issues
+			}
+		}
+
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				if page.FilePath == filePath {
+					return page.GetIssues()
+				}
+
+				for _, translation := range page.Translations {
+					if translation.FilePath == filePath {
+						return translation.GetIssues()
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+var regexIssueLine = regexp.MustCompile(`^line (\d+):`)
+
+// issueHeaderKeys maps the issues to the front matter keys they are about, the first matching entry is used
+var issueHeaderKeys = []struct {
+	contains, key string
+}{
+	{"slug", "slug"},
+	{"weight", "weight"},
+	{"state", "state"},
+	{"outside importance", "outsideImportance"},
+	{"importance", "audienceImportance"},
+	{"audience", "audience"},
+	{"tag", "tags"},
+	{"prerequisite", "prerequisites"},
+	{"title", "title"},
+	{"description", "description"},
+}
+
+// issueRange returns the line an issue is about: the line given in the issue, the line of the front matter key it is
+// about or the first line of the file
+func issueRange(text, issue string) lspRange {
+	rows := strings.Split(strings.Replace(text, "\r\n", EOL, -1), EOL)
+
+	line := 0
+
+	if matches := regexIssueLine.FindStringSubmatch(issue); matches != nil {
+		number, _ := strconv.Atoi(matches[1])
+		line = min(max(number-1, 0), len(rows)-1)
+	} else {
+		line = headerKeyLine(rows, issue)
+	}
+
+	return lspRange{Start: lspPosition{Line: line}, End: lspPosition{Line: line, Character: utf16Length(rows[line])}}
+}
+
+func headerKeyLine(rows []string, issue string) int {
+	end := frontMatterEnd(rows)
+
+	for _, entry := range issueHeaderKeys {
+		if !strings.Contains(issue, entry.contains) {
+			continue
+		}
+
+		for i := 1; i < end; i++ {
+			matches := regexHeader.FindStringSubmatch(rows[i])
+			if len(matches) == 3 && matches[1] == entry.key {
+				return i
+			}
+		}
+	}
+
+	return 0
+}
+
+func utf16Length(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+var regexStateMismatch = regexp.MustCompile(`^state mismatch\. got: .*, want: (\S+)$`)
+
+const (
+	issueFileName = "file name does not match the dash joined weight and slug"
+	issueSlug     = "slug does not match the lowercase title with dashes"
+)
+
+// codeActions returns the fixes of the fixable diagnostics of a document: renaming the file after its weight and slug,
+// setting the slug from the title and setting the calculated state
+func codeActions(uri, text string, diagnostics []lspDiagnostic) []lspCodeAction {
+	actions := []lspCodeAction{}
+
+	content, err := ParseMarkdown(text)
+	if err != nil {
+		return actions
+	}
+
+	for _, diagnostic := range diagnostics {
+		var (
+			title string
+			edit  lspWorkspaceEdit
+		)
+
+		switch {
+		case diagnostic.Message == issueFileName:
+			filePath := uriToPath(uri)
+
+			fileName := fmt.Sprintf("%s-%s.md", content.Weight, content.Slug)
+			if _, language := SplitLanguage(path.Base(filePath)); language != "" {
+				fileName = fmt.Sprintf("%s-%s.%s.md", content.Weight, content.Slug, language)
+			}
+
+			title = "Rename file to " + fileName
+			edit.DocumentChanges = []lspRenameFile{{Kind: "rename", OldURI: uri, NewURI: pathToURI(path.Join(path.Dir(filePath), fileName))}}
+
+		case strings.HasPrefix(diagnostic.Message, issueSlug):
+			slug := slugify(content.Title)
+
+			title = "Set slug to " + slug
+			edit.Changes = map[string][]lspTextEdit{uri: {replaceHeaderValue(text, "slug", slug)}}
+
+		case regexStateMismatch.MatchString(diagnostic.Message):
+			state := regexStateMismatch.FindStringSubmatch(diagnostic.Message)[1]
+
+			title = "Set state to " + state
+			edit.Changes = map[string][]lspTextEdit{uri: {replaceHeaderValue(text, "state", state)}}
+
+		default:
+			continue
+		}
+
+		actions = append(actions, lspCodeAction{Title: title, Kind: "quickfix", Diagnostics: []lspDiagnostic{diagnostic}, Edit: edit})
+	}
+
+	return actions
+}
+
+// replaceHeaderValue returns an edit replacing the whole document with the front matter key set to value, the quotes of
+// the current value are kept
+func replaceHeaderValue(text, key, value string) lspTextEdit {
+	quote := "'"
+	if matches := regexp.MustCompile(fmt.Sprintf(`(?m)^%s\s*=\s*"`, regexp.QuoteMeta(key))).FindString(text); matches != "" {
+		quote = `"`
+	}
+
+	newText, _ := setHeaderValue(text, key, quote+value+quote)
+
+	rows := strings.Split(text, EOL)
+
+	return lspTextEdit{
+		Range:   lspRange{End: lspPosition{Line: len(rows) - 1, Character: utf16Length(rows[len(rows)-1])}},
+		NewText: newText,
+	}
+}
+
+var (
+	regexCompletionHeader = regexp.MustCompile(`^(\w+)\s*=\s*(.*)$`)
+	regexCompletionBadge  = regexp.MustCompile(`{{<\s*badge-[\w-]*$`)
+)
+
+// complete returns the completion items at the given position of a document
+func (s *LanguageServer) complete(text string, position lspPosition) []lspCompletionItem {
+	items := []lspCompletionItem{}
+
+	rows := strings.Split(strings.Replace(text, "\r\n", EOL, -1), EOL)
+	if position.Line >= len(rows) {
+		return items
+	}
+
+	row := []rune(rows[position.Line])
+	prefix := string(utf16.Decode(utf16.Encode(row)[:min(position.Character, utf16Length(string(row)))]))
+
+	inFrontMatter := len(rows) > 0 && rows[0] == "+++" && position.Line > 0
+	if end := frontMatterEnd(rows); end > 0 && position.Line >= end-1 {
+		inFrontMatter = false
+	}
+
+	var values []string
+
+	if matches := regexCompletionHeader.FindStringSubmatch(prefix); inFrontMatter && matches != nil {
+		switch matches[1] {
+		case "audience":
+			for _, audience := range Audiences {
+				values = append(values, string(audience))
+			}
+		case "audienceImportance", "outsideImportance":
+			for _, importance := range Importances {
+				values = append(values, string(importance))
+			}
+		case "state":
+			values = []string{string(Stub), string(Incomplete), string(Complete)}
+		case "tags":
+			values = s.completionTags()
+		}
+	} else if !inFrontMatter && regexCompletionBadge.MatchString(prefix) {
+		for _, badge := range Badges {
+			values = append(values, badgePrefix+string(badge))
+		}
+	}
+
+	for _, value := range values {
+		items = append(items, lspCompletionItem{Label: value, Kind: lspCompletionValue})
+	}
+
+	return items
+}
+
+func (s *LanguageServer) completionTags() []string {
+	if len(s.tags) > 0 {
+		return s.tags
+	}
+
+	var tags []string
+	for _, stat := range s.courses.TagStats() {
+		tags = append(tags, stat.Tag)
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+func uriToPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+
+	return parsed.Path
+}
+
+func pathToURI(filePath string) string {
+	return (&url.URL{Scheme: "file", Path: filePath}).String()
+}
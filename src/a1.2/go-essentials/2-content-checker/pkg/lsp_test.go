@@ -0,0 +1,278 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lspTestPage = "+++\ntitle = 'Foo'\nslug = 'bar'\nweight = 10\nstate = 'complete'\naudience = \"all\"\n+++\n\n## Summary\n\n- foo\n"
+
+func lspFrame(t *testing.T, message any) []byte {
+	t.Helper()
+
+	body, err := json.Marshal(message)
+	require.NoError(t, err)
+
+	return []byte(fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body))
+}
+
+func lspReadAll(t *testing.T, output []byte) []map[string]any {
+	t.Helper()
+
+	var messages []map[string]any
+
+	reader := bufio.NewReader(bytes.NewReader(output))
+	for {
+		header, err := textproto.NewReader(reader).ReadMIMEHeader()
+		if err != nil {
+			break
+		}
+
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		require.NoError(t, err)
+
+		body := make([]byte, length)
+		_, err = io.ReadFull(reader, body)
+		require.NoError(t, err)
+
+		var message map[string]any
+		require.NoError(t, json.Unmarshal(body, &message))
+
+		messages = append(messages, message)
+	}
+
+	return messages
+}
+
+func TestLanguageServer_Serve(t *testing.T) {
+	analyze := func(documents map[string]string) (Courses, error) {
+		var courses Courses
+
+		for filePath, rawContent := range documents {
+			content, err := ParseMarkdown(rawContent)
+			if err != nil {
+				return nil, err
+			}
+
+			courses = courses.Add(filePath, "a1", "go", filepath.Base(filePath), content)
+		}
+
+		for i := range courses {
+			courses[i].Prepare()
+		}
+
+		return courses, nil
+	}
+
+	uri := "file:///site/content/a1/go/10-foo.md"
+
+	var input []byte
+	for _, message := range []any{
+		map[string]any{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]any{}},
+		map[string]any{"jsonrpc": "2.0", "method": "initialized", "params": map[string]any{}},
+		map[string]any{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]any{"textDocument": map[string]any{"uri": uri, "text": lspTestPage}}},
+		map[string]any{"jsonrpc": "2.0", "method": "textDocument/didChange", "params": map[string]any{"textDocument": map[string]any{"uri": uri}, "contentChanges": []any{map[string]any{"text": "no front matter"}}}},
+		map[string]any{"jsonrpc": "2.0", "id": 2, "method": "textDocument/hover", "params": map[string]any{}},
+		map[string]any{"jsonrpc": "2.0", "id": 3, "method": "shutdown"},
+		map[string]any{"jsonrpc": "2.0", "method": "exit"},
+	} {
+		input = append(input, lspFrame(t, message)...)
+	}
+
+	output := &bytes.Buffer{}
+
+	// execute
+	err := NewLanguageServer(bytes.NewReader(input), output, analyze, nil).Serve()
+
+	// verify
+	require.NoError(t, err)
+
+	messages := lspReadAll(t, output.Bytes())
+	require.Len(t, messages, 5)
+
+	assert.EqualValues(t, 1, messages[0]["id"])
+	assert.Contains(t, messages[0]["result"], "capabilities")
+
+	assert.Equal(t, "textDocument/publishDiagnostics", messages[1]["method"])
+	params := messages[1]["params"].(map[string]any)
+	assert.Equal(t, uri, params["uri"])
+
+	var got []string
+	for _, diagnostic := range params["diagnostics"].([]any) {
+		got = append(got, diagnostic.(map[string]any)["message"].(string))
+	}
+
+	assert.Equal(t, []string{
+		"main video problem [main-video-no-shortcode]: none of main-missing, main-really-missing, youtube, youtube-button found",
+		"state mismatch. got: complete, want: stub",
+		"topics section is missing",
+		"file name does not match the dash joined weight and slug",
+		"slug does not match the lowercase title with dashes (`bar`, `foo`)",
+	}, got)
+
+	// the document can not be parsed after the change
+	params = messages[2]["params"].(map[string]any)
+	diagnostics := params["diagnostics"].([]any)
+	require.Len(t, diagnostics, 1)
+	assert.Contains(t, diagnostics[0].(map[string]any)["message"], "markdown header could not be extracted")
+
+	assert.EqualValues(t, lspMethodNotFound, messages[3]["error"].(map[string]any)["code"])
+
+	assert.EqualValues(t, 3, messages[4]["id"])
+	assert.Contains(t, messages[4], "result")
+	assert.Nil(t, messages[4]["result"])
+}
+
+func TestLanguageServer_Serve_ExitWithoutShutdown(t *testing.T) {
+	input := lspFrame(t, map[string]any{"jsonrpc": "2.0", "method": "exit"})
+
+	// execute
+	err := NewLanguageServer(bytes.NewReader(input), &bytes.Buffer{}, nil, nil).Serve()
+
+	// verify
+	require.Error(t, err)
+}
+
+func Test_issueRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue string
+		want  lspRange
+	}{
+		{
+			name:  "line given",
+			issue: "line 11: code block without a language tag",
+			want:  lspRange{Start: lspPosition{Line: 10}, End: lspPosition{Line: 10, Character: 5}},
+		},
+		{
+			name:  "front matter key",
+			issue: "slug does not match the lowercase title with dashes (`bar`, `foo`)",
+			want:  lspRange{Start: lspPosition{Line: 2}, End: lspPosition{Line: 2, Character: 12}},
+		},
+		{
+			name:  "outside importance",
+			issue: "outside importance is invalid",
+			want:  lspRange{Start: lspPosition{Line: 0}, End: lspPosition{Line: 0, Character: 3}},
+		},
+		{
+			name:  "audience",
+			issue: "invalid audience: all",
+			want:  lspRange{Start: lspPosition{Line: 5}, End: lspPosition{Line: 5, Character: 16}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := issueRange(lspTestPage, tt.issue)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_codeActions(t *testing.T) {
+	uri := "file:///site/content/a1/go/10-foo.pl.md"
+	end := lspPosition{Line: 11, Character: 0}
+
+	diagnostics := []lspDiagnostic{
+		{Message: "file name does not match the dash joined weight and slug"},
+		{Message: "slug does not match the lowercase title with dashes (`bar`, `foo`)"},
+		{Message: "state mismatch. got: complete, want: stub"},
+		{Message: "topics section is missing"},
+	}
+
+	// execute
+	got := codeActions(uri, lspTestPage, diagnostics)
+
+	// verify
+	assert.Equal(t, []lspCodeAction{
+		{
+			Title:       "Rename file to 10-bar.pl.md",
+			Kind:        "quickfix",
+			Diagnostics: diagnostics[:1],
+			Edit: lspWorkspaceEdit{DocumentChanges: []lspRenameFile{
+				{Kind: "rename", OldURI: uri, NewURI: "file:///site/content/a1/go/10-bar.pl.md"},
+			}},
+		},
+		{
+			Title:       "Set slug to foo",
+			Kind:        "quickfix",
+			Diagnostics: diagnostics[1:2],
+			Edit: lspWorkspaceEdit{Changes: map[string][]lspTextEdit{uri: {{
+				Range:   lspRange{End: end},
+				NewText: "+++\ntitle = 'Foo'\nslug = 'foo'\nweight = 10\nstate = 'complete'\naudience = \"all\"\n+++\n\n## Summary\n\n- foo\n",
+			}}}},
+		},
+		{
+			Title:       "Set state to stub",
+			Kind:        "quickfix",
+			Diagnostics: diagnostics[2:3],
+			Edit: lspWorkspaceEdit{Changes: map[string][]lspTextEdit{uri: {{
+				Range:   lspRange{End: end},
+				NewText: "+++\ntitle = 'Foo'\nslug = 'bar'\nweight = 10\nstate = 'stub'\naudience = \"all\"\n+++\n\n## Summary\n\n- foo\n",
+			}}}},
+		},
+	}, got)
+}
+
+func TestLanguageServer_complete(t *testing.T) {
+	const text = "+++\naudience = \"\ntags = [\"go\", \"\n+++\n\n{{< badge-\n"
+
+	server := NewLanguageServer(nil, nil, nil, []string{"cli", "go"})
+
+	labels := func(items []lspCompletionItem) []string {
+		var result []string
+		for _, item := range items {
+			result = append(result, item.Label)
+		}
+
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		position lspPosition
+		want     []string
+	}{
+		{
+			name:     "audience",
+			position: lspPosition{Line: 1, Character: 12},
+			want:     []string{"all", "all professionals", "all developers", "Linux users", "Windows users", "Mac users", "web developers", "mobile developers", "desktop developers", "game developers", "sysadmins"},
+		},
+		{
+			name:     "tags",
+			position: lspPosition{Line: 2, Character: 15},
+			want:     []string{"cli", "go"},
+		},
+		{
+			name:     "badges",
+			position: lspPosition{Line: 5, Character: 10},
+			want:     []string{"badge-alternative", "badge-extra", "badge-fun", "badge-hint", "badge-must-see", "badge-summary", "badge-unchecked", "badge-no-embed", "badge-audio"},
+		},
+		{
+			name:     "nothing to complete",
+			position: lspPosition{Line: 4, Character: 0},
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := server.complete(text, tt.position)
+
+			// verify
+			assert.Equal(t, tt.want, labels(got))
+		})
+	}
+}
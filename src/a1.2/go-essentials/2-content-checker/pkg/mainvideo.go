@@ -0,0 +1,107 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MainVideoCode identifies why the main video of a page is a problem
+type MainVideoCode string
+
+const (
+	// MainVideoNoShortcode is used if neither a missing marker nor a video shortcode is found
+	MainVideoNoShortcode MainVideoCode = "main-video-no-shortcode"
+	// MainVideoMarkerAndVideo is used if both a missing marker and a video shortcode are found
+	MainVideoMarkerAndVideo MainVideoCode = "main-video-marker-and-video"
+	// MainVideoDuplicateMarker is used if more than one missing marker is found
+	MainVideoDuplicateMarker MainVideoCode = "main-video-duplicate-marker"
+)
+
+var (
+	mainVideoMarkers    = []string{"main-missing", "main-really-missing"}
+	mainVideoShortcodes = []string{"youtube", "youtube-button"}
+)
+
+// ShortcodePosition is a shortcode found in a markdown file
+type ShortcodePosition struct {
+	Name   string `json:"name"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// MainVideoProblem explains why the main video of a page is a problem
+type MainVideoProblem struct {
+	Code MainVideoCode `json:"code"`
+	// Shortcodes are the offending shortcodes in the order of the document
+	Shortcodes []ShortcodePosition `json:"shortcodes,omitempty"`
+}
+
+// Issue returns the issue of the problem, prefixed with the line of the first offending shortcode if there is any
+func (p MainVideoProblem) Issue() string {
+	var reason string
+
+	switch p.Code {
+	case MainVideoNoShortcode:
+		reason = "none of " + strings.Join(append(append([]string{}, mainVideoMarkers...), mainVideoShortcodes...), ", ") + " found"
+	case MainVideoMarkerAndVideo:
+		reason = "both a missing marker and a video found"
+	case MainVideoDuplicateMarker:
+		reason = "more than one missing marker found"
+	}
+
+	issue := fmt.Sprintf("main video problem [%s]: %s", p.Code, reason)
+
+	if len(p.Shortcodes) == 0 {
+		return issue
+	}
+
+	found := make([]string, 0, len(p.Shortcodes))
+	for _, shortcode := range p.Shortcodes {
+		found = append(found, fmt.Sprintf("%s (line %d)", shortcode.Name, shortcode.Line))
+	}
+
+	return fmt.Sprintf("line %d: %s: %s", p.Shortcodes[0].Line, issue, strings.Join(found, ", "))
+}
+
+// AnalyzeMainVideo returns the main video of a markdown file and the cause if it is a problem
+func AnalyzeMainVideo(content string) (MainVideo, *MainVideoProblem) {
+	return analyzeMainVideo(ParseDocument(content).Nodes)
+}
+
+// analyzeMainVideo finds the main video in the nodes of the main video section. A single missing marker sets the video
+// missing, video shortcodes without missing markers set it present, anything else is a problem.
+func analyzeMainVideo(nodes []*Node) (MainVideo, *MainVideoProblem) {
+	markers := findShortcodes(nodes, mainVideoMarkers...)
+	videos := findShortcodes(nodes, mainVideoShortcodes...)
+
+	switch {
+	case len(markers) == 0 && len(videos) > 0:
+		return VideoPresent, nil
+	case len(markers) == 0:
+		return VideoProblem, &MainVideoProblem{Code: MainVideoNoShortcode}
+	case len(videos) > 0:
+		return VideoProblem, &MainVideoProblem{Code: MainVideoMarkerAndVideo, Shortcodes: shortcodePositions(append(markers, videos...))}
+	case len(markers) > 1:
+		return VideoProblem, &MainVideoProblem{Code: MainVideoDuplicateMarker, Shortcodes: shortcodePositions(markers)}
+	}
+
+	if markers[0].Name == "main-really-missing" {
+		return VideoReallyMissing, nil
+	}
+
+	return VideoMissing, nil
+}
+
+func shortcodePositions(nodes []*Node) []ShortcodePosition {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Start.Before(nodes[j].Start)
+	})
+
+	positions := make([]ShortcodePosition, 0, len(nodes))
+	for _, node := range nodes {
+		positions = append(positions, ShortcodePosition{Name: node.Name, Line: node.Start.Line, Column: node.Start.Column})
+	}
+
+	return positions
+}
@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeMainVideo(t *testing.T) {
+	type args struct {
+		content string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		want        MainVideo
+		wantProblem *MainVideoProblem
+	}{
+		{
+			name: "video is present",
+			args: args{
+				content: "{{< youtube abc >}}\n",
+			},
+			want: VideoPresent,
+		},
+		{
+			name: "video is missing",
+			args: args{
+				content: "foo\n\n{{< main-missing >}}\n",
+			},
+			want: VideoMissing,
+		},
+		{
+			name: "video is really missing",
+			args: args{
+				content: "{{< main-really-missing >}}\n",
+			},
+			want: VideoReallyMissing,
+		},
+		{
+			name: "no shortcode",
+			args: args{
+				content: "foo\n",
+			},
+			want:        VideoProblem,
+			wantProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+		},
+		{
+			name: "missing marker and video",
+			args: args{
+				content: "{{< youtube abc >}}\n\nfoo {{< main-missing >}}\n",
+			},
+			want: VideoProblem,
+			wantProblem: &MainVideoProblem{
+				Code: MainVideoMarkerAndVideo,
+				Shortcodes: []ShortcodePosition{
+					{Name: "youtube", Line: 1, Column: 1},
+					{Name: "main-missing", Line: 3, Column: 5},
+				},
+			},
+		},
+		{
+			name: "duplicate missing markers",
+			args: args{
+				content: "{{< main-really-missing >}}\n{{< main-missing >}}\n",
+			},
+			want: VideoProblem,
+			wantProblem: &MainVideoProblem{
+				Code: MainVideoDuplicateMarker,
+				Shortcodes: []ShortcodePosition{
+					{Name: "main-really-missing", Line: 1, Column: 1},
+					{Name: "main-missing", Line: 2, Column: 1},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got, gotProblem := AnalyzeMainVideo(tt.args.content)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantProblem, gotProblem)
+		})
+	}
+}
+
+func TestMainVideoProblem_Issue(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem MainVideoProblem
+		want    string
+	}{
+		{
+			name:    "no shortcode",
+			problem: MainVideoProblem{Code: MainVideoNoShortcode},
+			want:    "main video problem [main-video-no-shortcode]: none of main-missing, main-really-missing, youtube, youtube-button found",
+		},
+		{
+			name: "offending shortcodes are listed with their lines",
+			problem: MainVideoProblem{
+				Code: MainVideoDuplicateMarker,
+				Shortcodes: []ShortcodePosition{
+					{Name: "main-missing", Line: 12, Column: 1},
+					{Name: "main-really-missing", Line: 14, Column: 3},
+				},
+			},
+			want: "line 12: main video problem [main-video-duplicate-marker]: more than one missing marker found: main-missing (line 12), main-really-missing (line 14)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := tt.problem.Issue()
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
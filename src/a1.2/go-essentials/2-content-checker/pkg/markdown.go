@@ -41,12 +41,14 @@ func ParseMarkdown(rawContent string) (Content, error) {
 	// Convert DOS/Windows line endings (\r\n) into Linux/Unix line endings
 	strContent := strings.Replace(rawContent, "\r\n", EOL, -1)
 
-	header, body, err := splitMarkdown(strContent)
+	header, _, err := splitMarkdown(strContent)
 	if err != nil {
 		return Content{}, fmt.Errorf("markdown header could not be extracted, err: %w", err)
 	}
 
-	sections := extractSection(body)
+	document := ParseDocument(strContent)
+
+	sections := document.Sections()
 	tags := getHeaderValues(header, "tags", nil)
 
 	var content Content
@@ -61,11 +63,16 @@ func ParseMarkdown(rawContent string) (Content, error) {
 	content.Slug = getHeaderValue(header, "slug", "")
 	content.Weight = getHeaderValue(header, "weight", "")
 	content.Title = getHeaderValue(header, "title", "")
+	content.Description = getHeaderValue(header, "description", "")
 	content.State = State(getHeaderValue(header, "state", ""))
 	content.Audience = Audience(getHeaderValue(header, "audience", ""))
 	content.Importance = Importance(getHeaderValue(header, "audienceImportance", ""))
 	content.OutsideImportance = Importance(getHeaderValue(header, "outsideImportance", ""))
 	content.Tags = tags
+	content.Prerequisites = getHeaderValues(header, "prerequisites", nil)
+	content.LastReviewed = getHeaderValue(header, "lastReviewed", "")
+	content.CodeBlocks = extractCodeBlocks(document)
+	content.SectionStats = measureSections(sections)
 
 	return content, nil
 }
@@ -122,9 +129,89 @@ func getHeaderValues(header, key string, defaultValue []string) []string {
 	return defaultValue
 }
 
+var regexRawHeaderValue = `(?m)^%s\s*=[^\r\n]*`
+
+// setHeaderValue replaces the value of a key in the front matter of a raw markdown file. If the key is missing, it is
+// added to the end of the front matter.
+func setHeaderValue(rawContent, key, value string) (string, bool) {
+	if !strings.HasPrefix(rawContent, "+++") {
+		return rawContent, false
+	}
+
+	end := strings.Index(rawContent[3:], "\n+++")
+	if end == -1 {
+		return rawContent, false
+	}
+
+	end += 3
+
+	header := rawContent[:end]
+	row := fmt.Sprintf("%s = %s", key, value)
+
+	regex := regexp.MustCompile(fmt.Sprintf(regexRawHeaderValue, regexp.QuoteMeta(key)))
+	if regex.MatchString(header) {
+		header = regex.ReplaceAllLiteralString(header, row)
+	} else {
+		header += EOL + row
+	}
+
+	return header + rawContent[end:], true
+}
+
+// replaceSection replaces the content of a section in a raw markdown file. If the section is missing, it is added to
+// the end of the file using a setext header.
+func replaceSection(rawContent, title, content string) string {
+	windows := strings.Contains(rawContent, "\r\n")
+
+	normalized := strings.Replace(rawContent, "\r\n", EOL, -1)
+	rows := strings.Split(normalized, EOL)
+
+	newRows := append([]string{""}, strings.Split(content, EOL)...)
+	newRows = append(newRows, "")
+
+	var result string
+
+	document := ParseDocument(normalized)
+
+	headings := document.sectionHeadings()
+	for i, index := range headings {
+		heading := document.Nodes[index]
+		if strings.ToLower(strings.Trim(heading.Text, " \t")) != title {
+			continue
+		}
+
+		end := len(rows)
+		if i+1 < len(headings) {
+			end = document.Nodes[headings[i+1]].Start.Line - 1
+		}
+
+		merged := append([]string{}, rows[:heading.EndLine]...)
+		merged = append(merged, newRows...)
+		merged = append(merged, rows[end:]...)
+
+		result = strings.Join(merged, EOL)
+
+		break
+	}
+
+	if result == "" {
+		headerTitle := strings.ToUpper(title[:1]) + title[1:]
+		result = strings.TrimRight(strings.Join(rows, EOL), EOL) + EOL + EOL +
+			headerTitle + EOL + strings.Repeat("-", len(headerTitle)) + EOL +
+			strings.Join(newRows, EOL)
+	}
+
+	if windows {
+		result = strings.Replace(result, EOL, "\r\n", -1)
+	}
+
+	return result
+}
+
 type Section struct {
 	Title   string
 	Content string
+	Nodes   []*Node
 }
 
 type Sections []Section
@@ -149,6 +236,17 @@ func (s Sections) Get(title string) string {
 	return ""
 }
 
+// Nodes returns the nodes of the given section
+func (s Sections) Nodes(title string) []*Node {
+	for _, section := range s {
+		if section.Title == title {
+			return section.Nodes
+		}
+	}
+
+	return nil
+}
+
 func (s Sections) Titles() []string {
 	keys := make([]string, 0, len(s))
 	for _, section := range s {
@@ -159,131 +257,75 @@ func (s Sections) Titles() []string {
 }
 
 func extractSection(body string) Sections {
-	var sections Sections
-
-	currentSection := "root"
-	sectionStart := 0
-
-	rows := strings.Split(body, EOL)
-	for i, row := range rows {
-		if len(row) >= 3 && row[:3] == "## " {
-			content := strings.Trim(strings.Join(rows[sectionStart:i], EOL), " \t\n")
-			sections = append(sections, Section{Title: currentSection, Content: content})
-
-			sectionStart = i + 1
-
-			currentSection = strings.ToLower(strings.Trim(row[3:], " \t"))
-
-			continue
-		}
-
-		if i > sectionStart && len(row) >= 3 && row[:3] == "---" {
-			// if the previous line is empty or non-existent, this is a horizontal rule, not a header
-			if i == 0 || len(rows[i-1]) == 0 {
-				continue
-			}
-
-			content := strings.Trim(strings.Join(rows[sectionStart:i-1], EOL), " \t\n")
-			sections = append(sections, Section{Title: currentSection, Content: content})
-
-			sectionStart = i + 1
-
-			currentSection = strings.ToLower(strings.Trim(rows[i-1], " \t"))
-
-			continue
-		}
-	}
-
-	if currentSection != "root" {
-		content := strings.Trim(strings.Join(rows[sectionStart:], EOL), " \t\n")
-		sections = append(sections, Section{Title: currentSection, Content: content})
-	}
-
-	// Remove the root section if it's empty
-	if len(sections) > 0 && sections[0].Content == "" {
-		sections = sections[1:]
-	}
-
-	return sections
+	return ParseDocument(body).Sections()
 }
 
-var regexMissing = regexp.MustCompile(`{{<\s*main-missing\s*>}}`)
-var regexReallyMissing = regexp.MustCompile(`{{<\s*main-really-missing\s*>}}`)
-var regexYoutube = regexp.MustCompile(`{{<\s*youtube(-button)?\s+([^>]*)\s*>}}`)
-
 func ExtractMainVideo(content string) MainVideo {
-	matchCount := 0
-	mainVideo := VideoProblem
+	mainVideo, _ := AnalyzeMainVideo(content)
 
-	matches := regexMissing.FindAllStringSubmatch(content, -1)
-	if len(matches) > 0 {
-		matchCount += len(matches)
-		mainVideo = VideoMissing
-	}
-
-	matches = regexReallyMissing.FindAllStringSubmatch(content, -1)
-	if len(matches) > 0 {
-		matchCount += len(matches)
-		mainVideo = VideoReallyMissing
-	}
+	return mainVideo
+}
 
-	matches = regexYoutube.FindAllStringSubmatch(content, -1)
-	if len(matches) > 0 {
-		if matchCount == 0 {
-			return VideoPresent
-		}
+func ExtractRelatedVideos(content string) RelatedVideos {
+	return extractRelatedVideos(ParseDocument(content).Nodes)
+}
 
-		matchCount++
+// extractRelatedVideos splits the nodes by the headings of level 3 and below, each part describing a video
+func extractRelatedVideos(nodes []*Node) RelatedVideos {
+	if len(nodes) == 0 {
+		return nil
 	}
 
-	if matchCount != 1 {
-		return VideoProblem
-	}
+	var part []*Node
 
-	return mainVideo
-}
+	relatedVideos := RelatedVideos{}
 
-var regexSubHeader = regexp.MustCompile(`\n####?#? .*\n`)
+	closePart := func() {
+		if len(part) == 0 {
+			return
+		}
 
-func ExtractRelatedVideos(content string) RelatedVideos {
-	if strings.TrimSpace(content) == "" {
-		return nil
+		if relatedVideo := extractRelatedVideo(part); relatedVideo.Valid {
+			relatedVideos = append(relatedVideos, relatedVideo)
+		}
+
+		part = nil
 	}
 
-	sections := regexSubHeader.Split("\n"+content, -1)
+	for _, node := range nodes {
+		if node.Kind == HeadingNode && node.Level >= 3 {
+			closePart()
 
-	relatedVideos := make(RelatedVideos, 0, len(sections))
-	for _, section := range sections {
-		if strings.TrimSpace(section) == "" {
 			continue
 		}
 
-		relatedVideo := extractRelatedVideo(section)
-
-		if relatedVideo.Valid {
-			relatedVideos = append(relatedVideos, relatedVideo)
-		}
+		part = append(part, node)
 	}
 
+	closePart()
+
 	return relatedVideos
 }
 
-var regexTime = regexp.MustCompile(`{{<\s*time\s+(\d+)\s*>}}`)
-
-func extractTime(content string) (int, []string) {
+func extractTime(nodes []*Node) (int, []string) {
 	var (
 		issues  []string
 		minutes int
 		err     error
 	)
 
-	timeMatches := regexTime.FindAllStringSubmatch(content, -1)
+	timeMatches := findShortcodes(nodes, "time")
 	if len(timeMatches) == 0 {
 		issues = append(issues, "missing time shortcode")
 	} else {
-		minutes, err = strconv.Atoi(timeMatches[0][1])
+		arg := ""
+		if len(timeMatches[0].Args) > 0 {
+			arg = timeMatches[0].Args[0]
+		}
+
+		minutes, err = strconv.Atoi(arg)
 		if err != nil {
-			issues = append(issues, fmt.Sprintf("failed to parse duration: %s", timeMatches[0][1]))
+			issues = append(issues, fmt.Sprintf("failed to parse duration: %s", arg))
 		}
 	}
 	if len(timeMatches) > 1 {
@@ -293,19 +335,22 @@ func extractTime(content string) (int, []string) {
 	return minutes, issues
 }
 
-var regexBadge = regexp.MustCompile(`{{<\s*badge-(\S*)\s*>}}`)
+const badgePrefix = "badge-"
 
-func extractBadges(content string) (Badge, bool, []string) {
+func extractBadges(nodes []*Node) (Badge, bool, []string) {
 	var (
 		badges []Badge
 		issues []string
 	)
 
 	noEmbed := false
-	badgeMatches := regexBadge.FindAllStringSubmatch(content, -1)
 
-	for _, match := range badgeMatches {
-		switch badge := Badge(match[1]); badge {
+	for _, shortcode := range findNodes(nodes, ShortcodeNode) {
+		if !strings.HasPrefix(shortcode.Name, badgePrefix) {
+			continue
+		}
+
+		switch badge := Badge(strings.TrimPrefix(shortcode.Name, badgePrefix)); badge {
 		case Unchecked, Alternative, Extra, Fun, Hint, MustSee, Summary:
 			badges = append(badges, badge)
 		case NoEmbed:
@@ -334,10 +379,10 @@ func extractBadges(content string) (Badge, bool, []string) {
 	return badges[0], noEmbed, issues
 }
 
-func extractYoutube(content string, noEmbed bool) (int, []string) {
+func extractYoutube(nodes []*Node, noEmbed bool) (int, []string) {
 	var issues []string
 
-	youtubeMatches := regexYoutube.FindAllStringSubmatch(content, -1)
+	youtubeMatches := findShortcodes(nodes, "youtube", "youtube-button")
 
 	switch len(youtubeMatches) {
 	case 0:
@@ -355,27 +400,27 @@ func extractYoutube(content string, noEmbed bool) (int, []string) {
 	return len(youtubeMatches), issues
 }
 
-func extractRelatedVideo(content string) RelatedVideo {
+func extractRelatedVideo(nodes []*Node) RelatedVideo {
 	var (
 		badge   Badge
 		issues  []string
 		minutes int
 	)
 
-	minutes, timeIssues := extractTime(content)
+	minutes, timeIssues := extractTime(nodes)
 	issues = append(issues, timeIssues...)
 
-	badge, noEmbed, badgeIssues := extractBadges(content)
+	badge, noEmbed, badgeIssues := extractBadges(nodes)
 	issues = append(issues, badgeIssues...)
 
-	ytCount, ytIssues := extractYoutube(content, noEmbed)
+	ytCount, ytIssues := extractYoutube(nodes, noEmbed)
 	issues = append(issues, ytIssues...)
 
 	if ytCount == 0 && !noEmbed && badge == "" && minutes == 0 {
 		return RelatedVideo{}
 	}
 
-	if minutes > 0 && badge != "" && strings.Index(content, "badge") < strings.Index(content, "time") {
+	if minutes > 0 && badge != "" && firstBadgeBeforeTime(nodes) {
 		issues = append(issues, "badge should be placed after time")
 	}
 
@@ -387,6 +432,18 @@ func extractRelatedVideo(content string) RelatedVideo {
 	}
 }
 
+func firstBadgeBeforeTime(nodes []*Node) bool {
+	times := findShortcodes(nodes, "time")
+
+	for _, shortcode := range findNodes(nodes, ShortcodeNode) {
+		if strings.HasPrefix(shortcode.Name, badgePrefix) {
+			return shortcode.Start.Before(times[0].Start)
+		}
+	}
+
+	return false
+}
+
 const (
 	tagUsefulWithoutVideo = "useful-without-video"
 	tagSlugForced         = "slug-forced"
@@ -400,8 +457,8 @@ func sectionsToDefaultBody(sections Sections, tags []string) DefaultBody {
 	hasRelatedLinks := sections.HasNonEmpty(sectionRelatedLinks)
 	hasExercises := sections.HasNonEmpty(sectionExercises)
 
-	mainVideo := ExtractMainVideo(sections.Get(sectionMainVideo))
-	relatedVideos := ExtractRelatedVideos(sections.Get(sectionRelatedVideos))
+	mainVideo, mainVideoProblem := analyzeMainVideo(sections.Nodes(sectionMainVideo))
+	relatedVideos := extractRelatedVideos(sections.Nodes(sectionRelatedVideos))
 
 	if hasExercises && strings.TrimSpace(sections.Get(sectionExercises)) == "" {
 		hasExercises = false
@@ -427,6 +484,7 @@ func sectionsToDefaultBody(sections Sections, tags []string) DefaultBody {
 
 	return DefaultBody{
 		MainVideo:          mainVideo,
+		MainVideoProblem:   mainVideoProblem,
 		HasSummary:         hasSummary,
 		HasTopics:          hasTopics,
 		RelatedVideos:      relatedVideos,
@@ -442,14 +500,192 @@ func sectionsToDefaultBody(sections Sections, tags []string) DefaultBody {
 func sectionsToIndexBody(sections Sections) *IndexBody {
 	return &IndexBody{
 		HasEpisodes:   sections.HasNonEmpty(sectionEpisodes),
+		Episodes:      extractEpisodes(sections.Nodes(sectionEpisodes)),
 		CompleteState: Incomplete,
 	}
 }
 
+// firstLink returns the first link of a list item, nil if there is none
+func firstLink(item *Node) *Node {
+	for _, node := range item.Inlines() {
+		if node.Kind == LinkNode {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// extractEpisodes collects the top level list items of the episodes section
+func extractEpisodes(nodes []*Node) []Episode {
+	var episodes []Episode
+
+	for _, list := range nodes {
+		if list.Kind != ListNode {
+			continue
+		}
+
+		for _, item := range list.Children {
+			if item.Text == "" {
+				continue
+			}
+
+			link := firstLink(item)
+			if link == nil {
+				episodes = append(episodes, Episode{Title: item.Text})
+
+				continue
+			}
+
+			episodes = append(episodes, Episode{Title: strings.TrimSpace(link.Text), Link: link.Destination})
+		}
+	}
+
+	return episodes
+}
+
 func sectionsToPracticeBody(sections Sections) *PracticeBody {
 	return &PracticeBody{
 		HasDescription:           sections.HasNonEmpty(sectionDescription),
 		HasRecommendedChallenges: sections.HasNonEmpty(sectionRecommendedChallenges),
 		HasAdditionalChallenges:  sections.HasNonEmpty(sectionAdditionalChallenges),
+		RecommendedChallenges:    extractChallenges(sections.Nodes(sectionRecommendedChallenges)),
+		AdditionalChallenges:     extractChallenges(sections.Nodes(sectionAdditionalChallenges)),
+		SectionTitles:            sections.Titles(),
+	}
+}
+
+// extractChallenges collects the challenges of a practice section. A challenge is either a top level list item linking
+// to the challenge or a level 3 header followed by the description of the challenge, linking to the challenge either in
+// the header or in the description.
+func extractChallenges(nodes []*Node) Challenges {
+	var (
+		challenges  Challenges
+		description []*Node
+		inHeader    bool
+	)
+
+	closeHeader := func() {
+		if !inHeader {
+			return
+		}
+
+		last := &challenges[len(challenges)-1]
+
+		if len(description) == 0 {
+			last.Issues = append(last.Issues, "empty challenge: "+last.Title)
+		}
+
+		// the link is either in the header or in the description
+		if last.Link == "" {
+			walkNodes(description, func(node *Node) bool {
+				if last.Link == "" && node.Kind == LinkNode {
+					last.Link = node.Destination
+				}
+
+				return last.Link == ""
+			})
+		}
+
+		if last.Link == "" {
+			last.Issues = append(last.Issues, "challenge without a link: "+last.Title)
+		}
+
+		last.Difficulty, last.Issues = extractDifficulty(description, last.Issues)
+
+		description = nil
+		inHeader = false
+	}
+
+	for _, node := range nodes {
+		if node.Kind == HeadingNode && node.Level == 3 {
+			closeHeader()
+
+			challenge := Challenge{Title: node.Text}
+			if link := firstLink(node); link != nil {
+				challenge.Title = strings.TrimSpace(link.Text)
+				challenge.Link = link.Destination
+			}
+
+			challenges = append(challenges, challenge)
+			inHeader = true
+
+			continue
+		}
+
+		if inHeader {
+			description = append(description, node)
+
+			continue
+		}
+
+		if node.Kind != ListNode {
+			continue
+		}
+
+		for _, item := range node.Children {
+			challenges = append(challenges, extractListChallenge(item))
+		}
+	}
+
+	closeHeader()
+
+	return challenges
+}
+
+func extractListChallenge(item *Node) Challenge {
+	var challenge Challenge
+
+	if item.Text == "" {
+		challenge.Issues = append(challenge.Issues, "empty challenge entry")
+
+		return challenge
+	}
+
+	inlines := item.Inlines()
+
+	if link := firstLink(item); link != nil {
+		challenge.Title = strings.TrimSpace(link.Text)
+		challenge.Link = link.Destination
+	} else {
+		title := item.Text
+		for _, shortcode := range findShortcodes(inlines, shortcodeDifficulty) {
+			title = strings.Replace(title, shortcode.Text, "", 1)
+		}
+
+		challenge.Title = strings.TrimSpace(title)
+	}
+
+	if challenge.Link == "" {
+		challenge.Issues = append(challenge.Issues, "challenge without a link: "+challenge.Title)
+	}
+
+	challenge.Difficulty, challenge.Issues = extractDifficulty(inlines, challenge.Issues)
+
+	return challenge
+}
+
+const shortcodeDifficulty = "difficulty"
+
+func extractDifficulty(nodes []*Node, issues []string) (Difficulty, []string) {
+	matches := findShortcodes(nodes, shortcodeDifficulty)
+	if len(matches) == 0 {
+		return "", issues
+	}
+
+	if len(matches) > 1 {
+		issues = append(issues, "multiple difficulty shortcodes found")
+	}
+
+	difficulty := Difficulty("")
+	if len(matches[0].Args) > 0 {
+		difficulty = Difficulty(matches[0].Args[0])
+	}
+
+	switch difficulty {
+	case Easy, Medium, Hard:
+		return difficulty, issues
+	default:
+		return "", append(issues, fmt.Sprintf("unknown difficulty: '%s'", difficulty))
 	}
 }
@@ -44,8 +44,9 @@ title = "Prepare"
 			want: Content{
 				Title: "Prepare",
 				Body: DefaultBody{
-					MainVideo:     VideoProblem,
-					SectionTitles: []string{},
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					SectionTitles:    []string{},
 				},
 			},
 		},
@@ -62,8 +63,9 @@ state = "incomplete"
 				Weight: "",
 				Slug:   "",
 				Body: DefaultBody{
-					MainVideo:     VideoProblem,
-					SectionTitles: []string{},
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					SectionTitles:    []string{},
 				},
 			},
 		},
@@ -84,8 +86,9 @@ title = "Prepare"
 			want: Content{
 				Title: "Prepare",
 				Body: DefaultBody{
-					MainVideo:     VideoProblem,
-					SectionTitles: []string{},
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					SectionTitles:    []string{},
 				},
 			},
 		},
@@ -99,8 +102,9 @@ state = "incomplete"
 			want: Content{
 				State: Incomplete,
 				Body: DefaultBody{
-					MainVideo:     VideoProblem,
-					SectionTitles: []string{},
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					SectionTitles:    []string{},
 				},
 			},
 		},
@@ -120,6 +124,7 @@ Episodes
 				Title: "Prepare",
 				Body: &IndexBody{
 					HasEpisodes:   true,
+					Episodes:      []Episode{{Title: "bar"}},
 					CompleteState: Incomplete,
 				},
 			},
@@ -142,6 +147,7 @@ Episodes
 				State: Complete,
 				Body: &IndexBody{
 					HasEpisodes:   true,
+					Episodes:      []Episode{{Title: "bar"}},
 					CompleteState: Incomplete,
 				},
 			},
@@ -188,12 +194,13 @@ Exercises
 				Weight: "",
 				Slug:   "",
 				Body: DefaultBody{
-					MainVideo:       VideoProblem,
-					HasSummary:      true,
-					HasTopics:       true,
-					HasExercises:    true,
-					HasRelatedLinks: true,
-					RelatedVideos:   RelatedVideos{},
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					HasSummary:       true,
+					HasTopics:        true,
+					HasExercises:     true,
+					HasRelatedLinks:  true,
+					RelatedVideos:    RelatedVideos{},
 					SectionTitles: []string{
 						sectionSummary,
 						sectionMainVideo,
@@ -249,12 +256,13 @@ Exercises
 				Weight: "",
 				Slug:   "",
 				Body: DefaultBody{
-					MainVideo:       VideoProblem,
-					HasSummary:      true,
-					HasTopics:       true,
-					HasExercises:    true,
-					HasRelatedLinks: true,
-					RelatedVideos:   RelatedVideos{},
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					HasSummary:       true,
+					HasTopics:        true,
+					HasExercises:     true,
+					HasRelatedLinks:  true,
+					RelatedVideos:    RelatedVideos{},
 					SectionTitles: []string{
 						sectionSummary,
 						sectionMainVideo,
@@ -300,12 +308,13 @@ state = "complete"
 				Weight: "",
 				Slug:   "",
 				Body: DefaultBody{
-					MainVideo:       VideoProblem,
-					HasSummary:      true,
-					HasTopics:       true,
-					HasExercises:    false,
-					HasRelatedLinks: true,
-					RelatedVideos:   RelatedVideos{},
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					HasSummary:       true,
+					HasTopics:        true,
+					HasExercises:     false,
+					HasRelatedLinks:  true,
+					RelatedVideos:    RelatedVideos{},
 					SectionTitles: []string{
 						sectionSummary,
 						sectionMainVideo,
@@ -355,12 +364,13 @@ weight = 9
 				Weight: "9",
 				Slug:   "",
 				Body: DefaultBody{
-					MainVideo:       VideoProblem,
-					HasSummary:      true,
-					HasTopics:       true,
-					HasExercises:    true,
-					HasRelatedLinks: true,
-					RelatedVideos:   RelatedVideos{},
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					HasSummary:       true,
+					HasTopics:        true,
+					HasExercises:     true,
+					HasRelatedLinks:  true,
+					RelatedVideos:    RelatedVideos{},
 					SectionTitles: []string{
 						sectionSummary,
 						sectionMainVideo,
@@ -519,6 +529,20 @@ Example output:
 					HasDescription:           true,
 					HasRecommendedChallenges: true,
 					HasAdditionalChallenges:  true,
+					RecommendedChallenges: Challenges{
+						{Title: "Display overall stats", Issues: []string{"challenge without a link: Display overall stats"}},
+						{Title: "Display stats for each chart", Issues: []string{"challenge without a link: Display stats for each chart"}},
+					},
+					AdditionalChallenges: Challenges{
+						{Title: "Sorting", Issues: []string{"challenge without a link: Sorting"}},
+						{Title: "Find the size of chart maps", Issues: []string{"challenge without a link: Find the size of chart maps"}},
+						{Title: "Find the size of intended chart maps and errors", Issues: []string{"challenge without a link: Find the size of intended chart maps and errors"}},
+					},
+					SectionTitles: []string{
+						sectionDescription,
+						sectionRecommendedChallenges,
+						sectionAdditionalChallenges,
+					},
 				},
 				Audience:   All,
 				Importance: Important,
@@ -893,10 +917,11 @@ Exercises
 				Weight: "40",
 				Slug:   "advanced-linux-commands",
 				Body: DefaultBody{
-					MainVideo:    VideoProblem,
-					HasSummary:   false,
-					HasTopics:    true,
-					HasExercises: false,
+					MainVideo:        VideoProblem,
+					MainVideoProblem: &MainVideoProblem{Code: MainVideoNoShortcode},
+					HasSummary:       false,
+					HasTopics:        true,
+					HasExercises:     false,
 					RelatedVideos: RelatedVideos{
 						{
 							Badge:   Alternative,
@@ -932,6 +957,9 @@ Exercises
 			require.NoError(t, err)
 
 			// verify
+			// section stats are covered by Test_measureSections
+			got.SectionStats = nil
+
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -1296,3 +1324,221 @@ probably my favorite video recommended on this page.
 		})
 	}
 }
+
+func Test_setHeaderValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		rawContent string
+		want       string
+		wantOK     bool
+	}{
+		{
+			name:       "replace",
+			rawContent: "+++\ntitle = 'foo'\nweight = 20\n+++\nweight = 20\n",
+			want:       "+++\ntitle = 'foo'\nweight = 30\n+++\nweight = 20\n",
+			wantOK:     true,
+		},
+		{
+			name:       "add",
+			rawContent: "+++\ntitle = 'foo'\n+++\n",
+			want:       "+++\ntitle = 'foo'\nweight = 30\n+++\n",
+			wantOK:     true,
+		},
+		{
+			name:       "windows line endings",
+			rawContent: "+++\r\nweight = 20\r\n+++\r\n",
+			want:       "+++\r\nweight = 30\r\n+++\r\n",
+			wantOK:     true,
+		},
+		{
+			name:       "no front matter",
+			rawContent: "weight = 20\n",
+			want:       "weight = 20\n",
+			wantOK:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got, gotOK := setHeaderValue(tt.rawContent, "weight", "30")
+
+			// verify
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOK, gotOK)
+		})
+	}
+}
+
+func Test_replaceSection(t *testing.T) {
+	tests := []struct {
+		name       string
+		rawContent string
+		want       string
+	}{
+		{
+			name:       "last section",
+			rawContent: "+++\ntitle = 'foo'\n+++\n\nEpisodes\n--------\n\n- old\n",
+			want:       "+++\ntitle = 'foo'\n+++\n\nEpisodes\n--------\n\n- new\n",
+		},
+		{
+			name:       "section followed by another",
+			rawContent: "+++\ntitle = 'foo'\n+++\n\n## Episodes\n- old\n- older\n## Notes\n\nbar\n",
+			want:       "+++\ntitle = 'foo'\n+++\n\n## Episodes\n\n- new\n\n## Notes\n\nbar\n",
+		},
+		{
+			name:       "missing section",
+			rawContent: "+++\ntitle = 'foo'\n+++\n\nbar\n\n\n",
+			want:       "+++\ntitle = 'foo'\n+++\n\nbar\n\nEpisodes\n--------\n\n- new\n",
+		},
+		{
+			name:       "windows line endings",
+			rawContent: "+++\r\ntitle = 'foo'\r\n+++\r\n\r\nEpisodes\r\n--------\r\n\r\n- old\r\n",
+			want:       "+++\r\ntitle = 'foo'\r\n+++\r\n\r\nEpisodes\r\n--------\r\n\r\n- new\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := replaceSection(tt.rawContent, sectionEpisodes, "- new")
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_extractChallenges(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Challenges
+	}{
+		{
+			name:    "empty",
+			content: "",
+			want:    nil,
+		},
+		{
+			name: "list items",
+			content: `{{<badge-extra>}}
+
+- [Tour of Go](https://go.dev/tour/) {{< difficulty easy >}}
+  - nested items are ignored
+- [Exercism](https://exercism.org/tracks/go)
+- Gophercises {{< difficulty hard >}}
+-
+- [Advent of Code](https://adventofcode.com/) {{< difficulty extreme >}}`,
+			want: Challenges{
+				{Title: "Tour of Go", Link: "https://go.dev/tour/", Difficulty: Easy},
+				{Title: "Exercism", Link: "https://exercism.org/tracks/go"},
+				{Title: "Gophercises", Difficulty: Hard, Issues: []string{"challenge without a link: Gophercises"}},
+				{Issues: []string{"empty challenge entry"}},
+				{Title: "Advent of Code", Link: "https://adventofcode.com/", Issues: []string{"unknown difficulty: 'extreme'"}},
+			},
+		},
+		{
+			name: "headers",
+			content: `### Sorting
+
+{{< difficulty medium >}}
+
+- lists inside challenges are part of the description, see [sort](https://pkg.go.dev/sort)
+
+### Empty
+
+### [Last](https://example.com/last)
+
+Write an app.
+
+### Unlinked
+
+Write another app.`,
+			want: Challenges{
+				{Title: "Sorting", Link: "https://pkg.go.dev/sort", Difficulty: Medium},
+				{Title: "Empty", Issues: []string{"empty challenge: Empty", "challenge without a link: Empty"}},
+				{Title: "Last", Link: "https://example.com/last"},
+				{Title: "Unlinked", Issues: []string{"challenge without a link: Unlinked"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := extractChallenges(ParseDocument(tt.content).Nodes)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_extractSection(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Sections
+	}{
+		{
+			name: "atx and setext headers",
+			body: "intro\n\n## Main Video\n\nfoo\n\nCode\n----\n\nbar",
+			want: Sections{
+				{Title: sectionRoot, Content: "intro"},
+				{Title: "main video", Content: "foo"},
+				{Title: "code", Content: "bar"},
+			},
+		},
+		{
+			name: "backtick fence",
+			body: "## Code\n\n```md\n## Not a section\n\nNot a section either\n---\n```\n\n## Summary\n\n- foo",
+			want: Sections{
+				{Title: "code", Content: "```md\n## Not a section\n\nNot a section either\n---\n```"},
+				{Title: "summary", Content: "- foo"},
+			},
+		},
+		{
+			name: "tilde fence with yaml documents",
+			body: "## Code\n\n~~~yaml\nfoo: 1\n---\nbar: 2\n~~~\n\n## Summary\n\n- foo",
+			want: Sections{
+				{Title: "code", Content: "~~~yaml\nfoo: 1\n---\nbar: 2\n~~~"},
+				{Title: "summary", Content: "- foo"},
+			},
+		},
+		{
+			name: "unclosed fence",
+			body: "## Code\n\n```go\n## foo\n",
+			want: Sections{
+				{Title: "code", Content: "```go\n## foo"},
+			},
+		},
+		{
+			name: "indented code",
+			body: "## Code\n\n    foo: 1\n---\n\n## Summary",
+			want: Sections{
+				{Title: "code", Content: "foo: 1\n---"},
+				{Title: "summary", Content: ""},
+			},
+		},
+		{
+			name: "html comment",
+			body: "## Code\n\n<!--\n## Summary\n\nTODO\n---\n-->\n\n## Summary",
+			want: Sections{
+				{Title: "code", Content: "<!--\n## Summary\n\nTODO\n---\n-->"},
+				{Title: "summary", Content: ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := extractSection(tt.body)
+
+			// verify
+			for i := range got {
+				// nodes are covered by TestParseDocument
+				got[i].Nodes = nil
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// weights of the factors of the priority score, every factor is between 0 and 1
+const (
+	nextImportanceWeight = 4.0
+	nextAudienceWeight   = 2.0
+	nextPositionWeight   = 2.0
+	nextFanOutWeight     = 2.0
+	nextDistanceWeight   = 1.0
+)
+
+type NextPage struct {
+	FilePath   string     `json:"filePath"`
+	Title      string     `json:"title"`
+	State      State      `json:"state"`
+	Importance Importance `json:"importance"`
+	Audience   Audience   `json:"audience"`
+	// Dependents is the number of pages depending on the page, directly or through other prerequisites
+	Dependents int     `json:"dependents"`
+	Score      float64 `json:"score"`
+	// Missing lists what to add to the page to reach the complete state
+	Missing []string `json:"missing"`
+}
+
+type NextPages []NextPage
+
+// NextPages ranks the pages which are not complete, the ones worth working on first are returned first. The score
+// combines the importance, the breadth of the audience, the position of the chapter in the course, the number of
+// dependent pages and the number of pieces missing for a complete page. If course is not empty, only the pages of the
+// given course are ranked. At most limit pages are returned, all of them if limit is not positive.
+func (c Courses) NextPages(course string, limit int) NextPages {
+	dependents := c.dependents()
+
+	var next NextPages
+
+	for _, loc := range c.locations() {
+		if course != "" && c[loc.course].Title != course {
+			continue
+		}
+
+		page := c.page(loc)
+		if page.GetState() == Complete || page.Content.Body == nil {
+			continue
+		}
+
+		chapters := c[loc.course].Chapters
+		missing := explainState(*page, chapters[loc.chapter].Pages).ToComplete()
+
+		nextPage := NextPage{
+			FilePath:   page.FilePath,
+			Title:      page.Content.Title,
+			State:      page.GetState(),
+			Importance: page.Content.Importance,
+			Audience:   page.Content.Audience,
+			Dependents: dependents[loc],
+			Missing:    nonNil(missing),
+		}
+
+		score := nextImportanceWeight*float64(max(page.Content.Importance.Level(), 0))/float64(len(Importances)) +
+			nextAudienceWeight*audienceBreadth(page.Content.Audience) +
+			nextPositionWeight*(1-float64(loc.chapter)/float64(len(chapters))) +
+			nextFanOutWeight*float64(nextPage.Dependents)/float64(nextPage.Dependents+1) +
+			nextDistanceWeight/float64(len(missing)+1)
+
+		nextPage.Score = float64(int(score*100+0.5)) / 100
+
+		next = append(next, nextPage)
+	}
+
+	// the order of the locations is kept for equal scores
+	sort.SliceStable(next, func(i, j int) bool {
+		return next[i].Score > next[j].Score
+	})
+
+	if limit > 0 && len(next) > limit {
+		next = next[:limit]
+	}
+
+	return next
+}
+
+// audienceBreadth returns 1 for the broadest audience and less for narrower ones, 0 for invalid audiences
+func audienceBreadth(audience Audience) float64 {
+	for i, a := range Audiences {
+		if a == audience {
+			return 1 - float64(i)/float64(len(Audiences))
+		}
+	}
+
+	return 0
+}
+
+// dependents returns the number of pages depending on each page, directly or transitively
+func (c Courses) dependents() map[pageLocation]int {
+	dependentsOf := make(map[pageLocation][]pageLocation)
+
+	for loc, prerequisites := range c.prerequisites(false) {
+		for _, prerequisite := range prerequisites {
+			dependentsOf[prerequisite] = append(dependentsOf[prerequisite], loc)
+		}
+	}
+
+	result := make(map[pageLocation]int, len(dependentsOf))
+
+	for loc := range dependentsOf {
+		visited := map[pageLocation]struct{}{loc: {}}
+		queue := []pageLocation{loc}
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			for _, dependent := range dependentsOf[current] {
+				if _, ok := visited[dependent]; ok {
+					continue
+				}
+
+				visited[dependent] = struct{}{}
+				queue = append(queue, dependent)
+			}
+		}
+
+		result[loc] = len(visited) - 1
+	}
+
+	return result
+}
+
+func (np NextPages) String() string {
+	var sb strings.Builder
+
+	for i, page := range np {
+		sb.WriteString(fmt.Sprintf("%2d. %s%s%s - %s%s%s, %s, %s, %d dependents, score: %.2f\n", i+1, cliBold, page.FilePath, cliReset, stateColor(page.State), page.State, cliReset, page.Importance, page.Audience, page.Dependents, page.Score))
+
+		for _, missing := range page.Missing {
+			sb.WriteString("      - " + missing + EOL)
+		}
+	}
+
+	return sb.String()
+}
+
+func (np NextPages) JSON() (string, error) {
+	if np == nil {
+		np = NextPages{}
+	}
+
+	raw, err := json.MarshalIndent(np, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw) + EOL, nil
+}
@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourses_NextPages(t *testing.T) {
+	pages := []Page{
+		{FilePath: "a1/go/_index.md", Content: Content{Body: &IndexBody{}}},
+		testPage("a1/go/10-intro.md", "intro", ""),
+		testPage("a1/go/20-tests.md", "tests", "", "intro"),
+		testPage("a1/rust/10-intro.md", "intro", ""),
+		testPage("a1/rust/20-cargo.md", "cargo", "", "go/tests"),
+		testPage("a2/go/10-generics.md", "generics", "", "/a1/go/tests/"),
+	}
+
+	type args struct {
+		course string
+		limit  int
+	}
+	tests := []struct {
+		name           string
+		args           args
+		prepare        func(c Courses)
+		wantPaths      []string
+		wantDependents []int
+		// wantMissing contains the missing pieces of the pages which are not default pages
+		wantMissing map[string][]string
+	}{
+		{
+			name:           "prerequisites and earlier chapters first",
+			wantPaths:      []string{"a1/go/10-intro.md", "a1/go/20-tests.md", "a2/go/10-generics.md", "a1/rust/10-intro.md", "a1/rust/20-cargo.md"},
+			wantDependents: []int{3, 2, 0, 0, 0},
+		},
+		{
+			name: "importance and audience outweigh position",
+			prepare: func(c Courses) {
+				c[0].Chapters[1].Pages[1].Content.Importance = Critical
+				c[0].Chapters[1].Pages[1].Content.Audience = All
+				c[0].Chapters[1].Pages[0].Content.Importance = Critical
+				c[0].Chapters[1].Pages[0].Content.Audience = SysAdmins
+			},
+			args:           args{limit: 3},
+			wantPaths:      []string{"a1/rust/20-cargo.md", "a1/rust/10-intro.md", "a1/go/10-intro.md"},
+			wantDependents: []int{0, 0, 3},
+		},
+		{
+			name: "complete pages are skipped",
+			prepare: func(c Courses) {
+				c[0].Chapters[0].Pages[1].Content.State = Complete
+			},
+			args:           args{course: "a1"},
+			wantPaths:      []string{"a1/go/20-tests.md", "a1/rust/10-intro.md", "a1/rust/20-cargo.md"},
+			wantDependents: []int{2, 0, 0},
+		},
+		{
+			name: "practice pages",
+			prepare: func(c Courses) {
+				c[1].Chapters[0].Pages[0].Content.Body = &PracticeBody{HasDescription: true}
+			},
+			args:           args{course: "a2"},
+			wantPaths:      []string{"a2/go/10-generics.md"},
+			wantDependents: []int{0},
+			wantMissing: map[string][]string{
+				"a2/go/10-generics.md": {"add a recommended challenges section", "add an additional challenges section"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			courses := testCourses(pages...)
+			if tt.prepare != nil {
+				tt.prepare(courses)
+			}
+
+			// execute
+			got := courses.NextPages(tt.args.course, tt.args.limit)
+
+			// verify
+			var paths []string
+			var dependents []int
+			for _, page := range got {
+				paths = append(paths, page.FilePath)
+				dependents = append(dependents, page.Dependents)
+
+				wantMissing, ok := tt.wantMissing[page.FilePath]
+				if !ok {
+					wantMissing = []string{"add a summary section", "add a non-empty exercises section", "add a youtube shortcode to the main video section"}
+				}
+
+				assert.Equal(t, wantMissing, page.Missing)
+			}
+
+			assert.Equal(t, tt.wantPaths, paths)
+			assert.Equal(t, tt.wantDependents, dependents)
+		})
+	}
+}
+
+func Test_audienceBreadth(t *testing.T) {
+	assert.Equal(t, 1.0, audienceBreadth(All))
+	assert.Greater(t, audienceBreadth(AllDevelopers), audienceBreadth(WebDevelopers))
+	assert.Equal(t, 0.0, audienceBreadth("nobody"))
+}
@@ -0,0 +1,338 @@
+package pkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type pageLocation struct {
+	course, chapter, page int
+}
+
+func (pl pageLocation) before(other pageLocation) bool {
+	if pl.course != other.course {
+		return pl.course < other.course
+	}
+
+	if pl.chapter != other.chapter {
+		return pl.chapter < other.chapter
+	}
+
+	return pl.page < other.page
+}
+
+func (c Courses) page(loc pageLocation) *Page {
+	return &c[loc.course].Chapters[loc.chapter].Pages[loc.page]
+}
+
+// locations returns the location of every non-index page in course, chapter and weight order
+func (c Courses) locations() []pageLocation {
+	var locations []pageLocation
+
+	for i, course := range c {
+		for j, chapter := range course.Chapters {
+			for k, page := range chapter.Pages {
+				if page.IsIndex() {
+					continue
+				}
+
+				locations = append(locations, pageLocation{course: i, chapter: j, page: k})
+			}
+		}
+	}
+
+	return locations
+}
+
+// resolvePrerequisite finds the pages a prerequisite refers to. A prerequisite is either a slug or a path made of
+// course, chapter and slug (or file name) segments, such as `go-basics/unit-tests` or `/a1.1/go-basics/unit-tests/`.
+// Matches in the same chapter are preferred over matches in the same course, which are preferred over the rest.
+func (c Courses) resolvePrerequisite(from pageLocation, ref string) []pageLocation {
+	segments := strings.Split(strings.Trim(ref, "/"), "/")
+
+	target := strings.TrimSuffix(segments[len(segments)-1], ".md")
+
+	chapterTitle := ""
+	if len(segments) > 1 {
+		chapterTitle = segments[len(segments)-2]
+	}
+
+	courseTitle := ""
+	if len(segments) > 2 {
+		courseTitle = segments[len(segments)-3]
+	}
+
+	var sameChapter, sameCourse, others []pageLocation
+
+	for _, loc := range c.locations() {
+		course := c[loc.course]
+		chapter := course.Chapters[loc.chapter]
+		page := chapter.Pages[loc.page]
+
+		if page.Content.Slug != target && strings.TrimSuffix(filepath.Base(page.FilePath), ".md") != target {
+			continue
+		}
+
+		if chapterTitle != "" && chapter.Title != chapterTitle || courseTitle != "" && course.Title != courseTitle {
+			continue
+		}
+
+		switch {
+		case loc.course == from.course && loc.chapter == from.chapter:
+			sameChapter = append(sameChapter, loc)
+		case loc.course == from.course:
+			sameCourse = append(sameCourse, loc)
+		default:
+			others = append(others, loc)
+		}
+	}
+
+	switch {
+	case len(sameChapter) > 0:
+		return sameChapter
+	case len(sameCourse) > 0:
+		return sameCourse
+	}
+
+	return others
+}
+
+// prerequisites returns the resolved prerequisites of every page, adding issues for the ones which cannot be resolved
+func (c Courses) prerequisites(addIssues bool) map[pageLocation][]pageLocation {
+	result := make(map[pageLocation][]pageLocation)
+
+	for _, loc := range c.locations() {
+		page := c.page(loc)
+
+		for _, ref := range page.Content.Prerequisites {
+			if ref == "" {
+				continue
+			}
+
+			var issue string
+
+			matches := c.resolvePrerequisite(loc, ref)
+
+			switch {
+			case len(matches) == 0:
+				issue = "prerequisite not found: " + ref
+			case len(matches) > 1:
+				issue = "prerequisite is ambiguous: " + ref
+			case matches[0] == loc:
+				issue = "page is its own prerequisite: " + ref
+			case !matches[0].before(loc):
+				issue = "prerequisite is not placed before the page: " + ref
+			}
+
+			if len(matches) == 1 && matches[0] != loc {
+				result[loc] = append(result[loc], matches[0])
+			}
+
+			if addIssues && issue != "" {
+				page.Issues = append(page.Issues, issue)
+			}
+		}
+	}
+
+	return result
+}
+
+// CheckPrerequisites adds issues to pages with prerequisites which are missing, ambiguous, placed after the page or
+// which form a cycle
+func (c Courses) CheckPrerequisites() {
+	edges := c.prerequisites(true)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make(map[pageLocation]int, len(edges))
+
+	var (
+		stack []pageLocation
+		visit func(loc pageLocation)
+	)
+
+	visit = func(loc pageLocation) {
+		states[loc] = visiting
+		stack = append(stack, loc)
+
+		for _, next := range edges[loc] {
+			switch states[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				c.reportCycle(loc, next, stack)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		states[loc] = visited
+	}
+
+	for _, loc := range c.locations() {
+		if states[loc] == unvisited {
+			visit(loc)
+		}
+	}
+}
+
+func (c Courses) reportCycle(from, to pageLocation, stack []pageLocation) {
+	start := len(stack) - 1
+	for start > 0 && stack[start] != to {
+		start--
+	}
+
+	filePaths := make([]string, 0, len(stack)-start+1)
+	for _, loc := range stack[start:] {
+		filePaths = append(filePaths, c.page(loc).FilePath)
+	}
+
+	filePaths = append(filePaths, c.page(to).FilePath)
+
+	page := c.page(from)
+	page.Issues = append(page.Issues, "prerequisite cycle: "+strings.Join(filePaths, " -> "))
+}
+
+type GraphNode struct {
+	FilePath string
+	Title    string
+	Course   string
+}
+
+type GraphEdge struct {
+	// From is the file path of the prerequisite
+	From string
+	// To is the file path of the page depending on the prerequisite
+	To string
+}
+
+type PrerequisiteGraph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// PrerequisiteGraph returns the dependency graph of the pages. If course is not empty, only the pages of the given
+// course and their prerequisites are included.
+func (c Courses) PrerequisiteGraph(course string) PrerequisiteGraph {
+	var graph PrerequisiteGraph
+
+	included := make(map[pageLocation]struct{})
+
+	include := func(loc pageLocation) {
+		if _, ok := included[loc]; ok {
+			return
+		}
+
+		included[loc] = struct{}{}
+
+		page := c.page(loc)
+		graph.Nodes = append(graph.Nodes, GraphNode{FilePath: page.FilePath, Title: page.Content.Title, Course: c[loc.course].Title})
+	}
+
+	edges := c.prerequisites(false)
+
+	for _, loc := range c.locations() {
+		if course != "" && c[loc.course].Title != course {
+			continue
+		}
+
+		include(loc)
+
+		for _, prerequisite := range edges[loc] {
+			include(prerequisite)
+
+			graph.Edges = append(graph.Edges, GraphEdge{From: c.page(prerequisite).FilePath, To: c.page(loc).FilePath})
+		}
+	}
+
+	return graph
+}
+
+// nodesByCourse groups the nodes by course, keeping the order of the courses
+func (pg PrerequisiteGraph) nodesByCourse() ([]string, map[string][]GraphNode) {
+	var courses []string
+
+	nodes := make(map[string][]GraphNode)
+	for _, node := range pg.Nodes {
+		if _, ok := nodes[node.Course]; !ok {
+			courses = append(courses, node.Course)
+		}
+
+		nodes[node.Course] = append(nodes[node.Course], node)
+	}
+
+	return courses, nodes
+}
+
+func graphLabel(node GraphNode) string {
+	if node.Title != "" {
+		return node.Title
+	}
+
+	return node.FilePath
+}
+
+// Dot returns the graph in the Graphviz DOT language, grouping the pages of each course into a cluster
+func (pg PrerequisiteGraph) Dot() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph prerequisites {\n")
+	sb.WriteString("  rankdir=LR;\n")
+
+	courses, nodes := pg.nodesByCourse()
+	for i, course := range courses {
+		sb.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", i))
+		sb.WriteString(fmt.Sprintf("    label=%q;\n", course))
+
+		for _, node := range nodes[course] {
+			sb.WriteString(fmt.Sprintf("    %q [label=%q];\n", node.FilePath, graphLabel(node)))
+		}
+
+		sb.WriteString("  }\n")
+	}
+
+	for _, edge := range pg.Edges {
+		sb.WriteString(fmt.Sprintf("  %q -> %q;\n", edge.From, edge.To))
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart, grouping the pages of each course into a subgraph
+func (pg PrerequisiteGraph) Mermaid() string {
+	ids := make(map[string]string, len(pg.Nodes))
+	for i, node := range pg.Nodes {
+		ids[node.FilePath] = fmt.Sprintf("n%d", i)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("flowchart LR\n")
+
+	courses, nodes := pg.nodesByCourse()
+	for i, course := range courses {
+		sb.WriteString(fmt.Sprintf("  subgraph c%d [\"%s\"]\n", i, mermaidEscape(course)))
+
+		for _, node := range nodes[course] {
+			sb.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", ids[node.FilePath], mermaidEscape(graphLabel(node))))
+		}
+
+		sb.WriteString("  end\n")
+	}
+
+	for _, edge := range pg.Edges {
+		sb.WriteString(fmt.Sprintf("  %s --> %s\n", ids[edge.From], ids[edge.To]))
+	}
+
+	return sb.String()
+}
+
+func mermaidEscape(label string) string {
+	return strings.Replace(label, `"`, "#quot;", -1)
+}
@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourses_CheckPrerequisites(t *testing.T) {
+	tests := []struct {
+		name    string
+		courses Courses
+		want    map[string][]string
+	}{
+		{
+			name: "valid",
+			courses: testCourses(
+				Page{FilePath: "a1/go/_index.md", Content: Content{Body: &IndexBody{}}},
+				testPage("a1/go/10-intro.md", "intro", ""),
+				testPage("a1/go/20-tests.md", "tests", "", "intro"),
+				testPage("a1/rust/10-intro.md", "intro", ""),
+				testPage("a1/rust/20-cargo.md", "cargo", "", "intro", "go/tests"),
+				testPage("a2/go/10-generics.md", "generics", "", "/a1/go/tests/", "a1/rust/10-intro.md", ""),
+			),
+			want: map[string][]string{},
+		},
+		{
+			name: "missing, ambiguous and self",
+			courses: testCourses(
+				testPage("a1/go/10-intro.md", "intro", ""),
+				testPage("a1/go/20-tests.md", "tests", "", "tests", "cargo-workspaces"),
+				testPage("a1/rust/10-intro.md", "intro", ""),
+				testPage("a2/go/10-generics.md", "generics", "", "intro"),
+			),
+			want: map[string][]string{
+				"a1/go/20-tests.md": {
+					"page is its own prerequisite: tests",
+					"prerequisite not found: cargo-workspaces",
+				},
+				"a2/go/10-generics.md": {"prerequisite is ambiguous: intro"},
+			},
+		},
+		{
+			name: "later page and cycle",
+			courses: testCourses(
+				testPage("a1/go/10-intro.md", "intro", "", "tests"),
+				testPage("a1/go/20-tests.md", "tests", "", "intro"),
+			),
+			want: map[string][]string{
+				"a1/go/10-intro.md": {
+					"prerequisite is not placed before the page: tests",
+				},
+				"a1/go/20-tests.md": {
+					"prerequisite cycle: a1/go/10-intro.md -> a1/go/20-tests.md -> a1/go/10-intro.md",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			courses := tt.courses
+
+			// execute
+			courses.CheckPrerequisites()
+
+			// verify
+			got := map[string][]string{}
+			for _, course := range courses {
+				for _, chapter := range course.Chapters {
+					for _, page := range chapter.Pages {
+						if len(page.Issues) > 0 {
+							got[page.FilePath] = page.Issues
+						}
+					}
+				}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCourses_PrerequisiteGraph(t *testing.T) {
+	courses := testCourses(
+		Page{FilePath: "a1/go/_index.md", Content: Content{Body: &IndexBody{}}},
+		testPage("a1/go/10-intro.md", "intro", ""),
+		testPage("a1/go/20-tests.md", "tests", ""),
+		testPage("a1/rust/10-intro.md", "intro", ""),
+		testPage("a1/rust/20-cargo.md", "cargo", "", "go/tests"),
+		testPage("a2/go/10-generics.md", "generics", "", "a1/go/tests"),
+	)
+
+	t.Run("dot", func(t *testing.T) {
+		// execute
+		got := courses.PrerequisiteGraph("a2").Dot()
+
+		// verify
+		assert.Equal(t, `digraph prerequisites {
+  rankdir=LR;
+  subgraph cluster_0 {
+    label="a2";
+    "a2/go/10-generics.md" [label="generics"];
+  }
+  subgraph cluster_1 {
+    label="a1";
+    "a1/go/20-tests.md" [label="tests"];
+  }
+  "a1/go/20-tests.md" -> "a2/go/10-generics.md";
+}
+`, got)
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		// execute
+		got := courses.PrerequisiteGraph("").Mermaid()
+
+		// verify
+		assert.Equal(t, `flowchart LR
+  subgraph c0 ["a1"]
+    n0["intro"]
+    n1["tests"]
+    n2["intro"]
+    n3["cargo"]
+  end
+  subgraph c1 ["a2"]
+    n4["generics"]
+  end
+  n1 --> n3
+  n1 --> n4
+`, got)
+	})
+}
@@ -0,0 +1,278 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultWeightStep = 10
+
+type ReorderOperation struct {
+	Move     string
+	After    string
+	Renumber bool
+	Step     int
+}
+
+type reorderEntry struct {
+	filePath string
+	weight   int
+	slug     string
+}
+
+// PlanReorder calculates the weight changes, file renames and link updates needed to reorder the pages of a chapter.
+// files must contain every markdown file which might link to a page of the chapter, keyed by their paths.
+func PlanReorder(files map[string]string, chapterDir string, op ReorderOperation) (FileChanges, error) {
+	if op.Step <= 0 {
+		op.Step = defaultWeightStep
+	}
+
+	entries, err := collectReorderEntries(files, chapterDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var weights map[string]int
+
+	switch {
+	case op.Renumber:
+		weights = renumberEntries(entries, op.Step)
+	case op.Move != "":
+		weights, err = moveEntry(entries, op.Move, op.After, op.Step)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("either a page to move or renumbering is required")
+	}
+
+	renames := make(map[string]string)
+	for _, entry := range entries {
+		weight := weights[entry.filePath]
+		if weight == entry.weight {
+			continue
+		}
+
+		renames[entry.filePath] = renameForWeight(entry.filePath, files[entry.filePath], weight)
+	}
+
+	for filePath := range files {
+		fileName, language := SplitLanguage(filepath.Base(filePath))
+		if language == "" || filepath.Dir(filePath) != filepath.Clean(chapterDir) {
+			continue
+		}
+
+		source := filepath.Join(filepath.Dir(filePath), fileName)
+		if _, ok := renames[source]; !ok {
+			continue
+		}
+
+		weights[filePath] = weights[source]
+		renames[filePath] = renameForWeight(filePath, files[filePath], weights[source])
+	}
+
+	var plan FileChanges
+
+	filePaths := make([]string, 0, len(files))
+	for filePath := range files {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	for _, filePath := range filePaths {
+		before := files[filePath]
+
+		after := before
+		if _, ok := renames[filePath]; ok {
+			after, _ = setHeaderValue(after, "weight", strconv.Itoa(weights[filePath]))
+		}
+		after = updateLinks(after, filePath, renames)
+
+		newFilePath, renamed := renames[filePath]
+		if !renamed && after == before {
+			continue
+		}
+
+		plan = append(plan, FileChange{FilePath: filePath, NewFilePath: newFilePath, Before: before, After: after})
+	}
+
+	return plan, nil
+}
+
+func collectReorderEntries(files map[string]string, chapterDir string) ([]reorderEntry, error) {
+	chapterDir = filepath.Clean(chapterDir)
+
+	var entries []reorderEntry
+
+	for filePath, rawContent := range files {
+		if filepath.Dir(filePath) != chapterDir || filepath.Base(filePath) == "_index.md" {
+			continue
+		}
+
+		// translations follow their source pages
+		if _, language := SplitLanguage(filepath.Base(filePath)); language != "" {
+			continue
+		}
+
+		content, err := ParseMarkdown(rawContent)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse markdown: %s, err: %w", filePath, err)
+		}
+
+		weight, ok := content.WeightNumber()
+		if !ok {
+			return nil, fmt.Errorf("page weight is not a number: %s (`%s`)", filePath, content.Weight)
+		}
+
+		entries = append(entries, reorderEntry{filePath: filePath, weight: weight, slug: content.Slug})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no pages found in chapter: %s", chapterDir)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].weight == entries[j].weight {
+			return entries[i].filePath < entries[j].filePath
+		}
+
+		return entries[i].weight < entries[j].weight
+	})
+
+	return entries, nil
+}
+
+func renumberEntries(entries []reorderEntry, step int) map[string]int {
+	weights := make(map[string]int, len(entries))
+
+	for i, entry := range entries {
+		weights[entry.filePath] = (i + 1) * step
+	}
+
+	return weights
+}
+
+func findEntry(entries []reorderEntry, slug string) int {
+	for i, entry := range entries {
+		if entry.slug == slug {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// moveEntry places the moved page right after another one. If the weights of the new neighbours leave no room for
+// the moved page, the whole chapter is renumbered.
+func moveEntry(entries []reorderEntry, slug, after string, step int) (map[string]int, error) {
+	from := findEntry(entries, slug)
+	if from < 0 {
+		return nil, fmt.Errorf("page to move not found: %s", slug)
+	}
+
+	moved := entries[from]
+
+	ordered := make([]reorderEntry, 0, len(entries))
+	ordered = append(ordered, entries[:from]...)
+	ordered = append(ordered, entries[from+1:]...)
+
+	to := 0
+	if after != "" {
+		idx := findEntry(ordered, after)
+		if idx < 0 {
+			return nil, fmt.Errorf("page to move after not found: %s", after)
+		}
+
+		to = idx + 1
+	}
+
+	ordered = append(ordered[:to], append([]reorderEntry{moved}, ordered[to:]...)...)
+
+	weights := make(map[string]int, len(ordered))
+	for _, entry := range ordered {
+		weights[entry.filePath] = entry.weight
+	}
+
+	prev := 0
+	if to > 0 {
+		prev = ordered[to-1].weight
+	}
+
+	switch {
+	case moved.weight > prev && (to == len(ordered)-1 || moved.weight < ordered[to+1].weight):
+		// the page is already in the right place
+	case to == len(ordered)-1:
+		weights[moved.filePath] = prev + step
+	case ordered[to+1].weight-prev >= 2:
+		weights[moved.filePath] = prev + (ordered[to+1].weight-prev)/2
+	default:
+		return renumberEntries(ordered, step), nil
+	}
+
+	return weights, nil
+}
+
+func renameForWeight(filePath, rawContent string, weight int) string {
+	dir, filename := filepath.Split(filePath)
+
+	content, err := ParseMarkdown(rawContent)
+	if err == nil && strings.HasPrefix(filename, content.Weight+"-") {
+		filename = strings.TrimPrefix(filename, content.Weight+"-")
+	}
+
+	return filepath.Join(dir, fmt.Sprintf("%d-%s", weight, filename))
+}
+
+var regexLinkTarget = regexp.MustCompile(`(\]\(|(?:rel)?ref\s+")([^)"\s#]+)`)
+
+// updateLinks rewrites markdown links and ref shortcodes pointing to renamed files
+func updateLinks(rawContent, filePath string, renames map[string]string) string {
+	if len(renames) == 0 {
+		return rawContent
+	}
+
+	return regexLinkTarget.ReplaceAllStringFunc(rawContent, func(match string) string {
+		parts := regexLinkTarget.FindStringSubmatch(match)
+		target := parts[2]
+
+		for oldPath, newPath := range renames {
+			if !linksTo(filePath, target, oldPath) {
+				continue
+			}
+
+			return parts[1] + strings.TrimSuffix(target, filepath.Base(oldPath)) + filepath.Base(newPath)
+		}
+
+		return match
+	})
+}
+
+func linksTo(filePath, target, linkedPath string) bool {
+	if path.Base(target) != filepath.Base(linkedPath) {
+		return false
+	}
+
+	chapterDir := filepath.Dir(linkedPath)
+
+	dir := path.Dir(target)
+	if dir == "." {
+		return filepath.Dir(filePath) == chapterDir
+	}
+
+	if path.Base(dir) != filepath.Base(chapterDir) {
+		return false
+	}
+
+	courseDir := path.Base(path.Dir(dir))
+	if courseDir == "." || courseDir == ".." || courseDir == "/" {
+		return true
+	}
+
+	return courseDir == filepath.Base(filepath.Dir(chapterDir))
+}
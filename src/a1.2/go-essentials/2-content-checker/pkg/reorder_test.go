@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reorderTestPage(weight, slug string) string {
+	return "+++\ntitle = '" + slug + "'\nweight = " + weight + "\nslug = '" + slug + "'\n+++\n\nSummary\n-------\n"
+}
+
+func TestPlanReorder(t *testing.T) {
+	files := map[string]string{
+		"content/a1/go/_index.md":   "+++\ntitle = 'Go'\n+++\n\nEpisodes\n--------\n\n- [foo]({{< ref \"10-foo.md\" >}})\n",
+		"content/a1/go/10-foo.md":   reorderTestPage("10", "foo"),
+		"content/a1/go/20-bar.md":   reorderTestPage("20", "bar"),
+		"content/a1/go/30-baz.md":   reorderTestPage("30", "baz") + "\nSee [bar](20-bar.md#topics)\n",
+		"content/a1/rust/10-foo.md": reorderTestPage("10", "foo") + "\nSee [go](../go/10-foo.md) and [rust](10-foo.md)\n",
+	}
+
+	t.Run("move into gap", func(t *testing.T) {
+		// execute
+		plan, err := PlanReorder(files, "content/a1/go", ReorderOperation{Move: "baz", After: "foo"})
+		require.NoError(t, err)
+
+		// verify
+		require.Len(t, plan, 1)
+		assert.Equal(t, "content/a1/go/30-baz.md", plan[0].FilePath)
+		assert.Equal(t, "content/a1/go/15-baz.md", plan[0].NewFilePath)
+		assert.Contains(t, plan[0].After, "weight = 15\n")
+	})
+
+	t.Run("move to the front", func(t *testing.T) {
+		// execute
+		plan, err := PlanReorder(files, "content/a1/go", ReorderOperation{Move: "bar"})
+		require.NoError(t, err)
+
+		// verify
+		require.Len(t, plan, 2)
+		assert.Equal(t, "content/a1/go/20-bar.md", plan[0].FilePath)
+		assert.Equal(t, "content/a1/go/5-bar.md", plan[0].NewFilePath)
+		assert.Equal(t, "content/a1/go/30-baz.md", plan[1].FilePath)
+		assert.Contains(t, plan[1].After, "See [bar](5-bar.md#topics)")
+	})
+
+	t.Run("renumber without room", func(t *testing.T) {
+		tightFiles := map[string]string{
+			"content/a1/go/1-foo.md": reorderTestPage("1", "foo"),
+			"content/a1/go/2-bar.md": reorderTestPage("2", "bar"),
+			"content/a1/go/3-baz.md": reorderTestPage("3", "baz"),
+		}
+
+		// execute
+		plan, err := PlanReorder(tightFiles, "content/a1/go", ReorderOperation{Move: "baz", After: "foo", Step: 10})
+		require.NoError(t, err)
+
+		// verify
+		renames := map[string]string{}
+		for _, change := range plan {
+			renames[change.FilePath] = change.NewFilePath
+		}
+
+		assert.Equal(t, map[string]string{
+			"content/a1/go/1-foo.md": "content/a1/go/10-foo.md",
+			"content/a1/go/2-bar.md": "content/a1/go/30-bar.md",
+			"content/a1/go/3-baz.md": "content/a1/go/20-baz.md",
+		}, renames)
+	})
+
+	t.Run("renumber updates links", func(t *testing.T) {
+		// execute
+		plan, err := PlanReorder(files, "content/a1/go", ReorderOperation{Renumber: true, Step: 100})
+		require.NoError(t, err)
+
+		// verify
+		changes := map[string]FileChange{}
+		for _, change := range plan {
+			changes[change.FilePath] = change
+		}
+
+		require.Len(t, changes, 5)
+		assert.Contains(t, changes["content/a1/go/_index.md"].After, `{{< ref "100-foo.md" >}}`)
+		assert.Contains(t, changes["content/a1/go/30-baz.md"].After, "See [bar](200-bar.md#topics)")
+		assert.Contains(t, changes["content/a1/rust/10-foo.md"].After, "See [go](../go/100-foo.md) and [rust](10-foo.md)")
+		assert.False(t, changes["content/a1/rust/10-foo.md"].IsRename())
+	})
+
+	t.Run("translations follow their source pages", func(t *testing.T) {
+		translatedFiles := map[string]string{
+			"content/a1/go/10-foo.md":    reorderTestPage("10", "foo"),
+			"content/a1/go/20-bar.md":    reorderTestPage("20", "bar"),
+			"content/a1/go/20-bar.pl.md": reorderTestPage("20", "bar"),
+		}
+
+		// execute
+		plan, err := PlanReorder(translatedFiles, "content/a1/go", ReorderOperation{Move: "bar"})
+		require.NoError(t, err)
+
+		// verify
+		require.Len(t, plan, 2)
+		assert.Equal(t, "content/a1/go/20-bar.md", plan[0].FilePath)
+		assert.Equal(t, "content/a1/go/5-bar.md", plan[0].NewFilePath)
+		assert.Equal(t, "content/a1/go/20-bar.pl.md", plan[1].FilePath)
+		assert.Equal(t, "content/a1/go/5-bar.pl.md", plan[1].NewFilePath)
+		assert.Contains(t, plan[1].After, "weight = 5\n")
+	})
+
+	t.Run("unknown page", func(t *testing.T) {
+		// execute
+		_, err := PlanReorder(files, "content/a1/go", ReorderOperation{Move: "qux"})
+
+		// verify
+		require.Error(t, err)
+	})
+
+	t.Run("non-numeric weight", func(t *testing.T) {
+		brokenFiles := map[string]string{
+			"content/a1/go/foo.md": reorderTestPage("'a'", "foo"),
+		}
+
+		// execute
+		_, err := PlanReorder(brokenFiles, "content/a1/go", ReorderOperation{Renumber: true})
+
+		// verify
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,236 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SectionRule describes the expected shape of a section, zero values disable the given check
+type SectionRule struct {
+	MinWords int `json:"minWords,omitempty"`
+	MaxWords int `json:"maxWords,omitempty"`
+	// RequireList requires the section to contain a list
+	RequireList bool `json:"requireList,omitempty"`
+	// MinItems and MaxItems limit the number of top level list items of the section
+	MinItems int `json:"minItems,omitempty"`
+	MaxItems int `json:"maxItems,omitempty"`
+	// MinReadability is the minimum Flesch reading ease of the section
+	MinReadability float64 `json:"minReadability,omitempty"`
+}
+
+// SectionStat contains the measurements of the prose of a section, code, comments and shortcodes are not counted
+type SectionStat struct {
+	Title     string
+	Words     int
+	Sentences int
+	Syllables int
+	// Lists and ListItems count the top level lists and their items only
+	Lists     int
+	ListItems int
+}
+
+type SectionStats []SectionStat
+
+func (ss SectionStats) Get(title string) (SectionStat, bool) {
+	for _, stat := range ss {
+		if stat.Title == title {
+			return stat, true
+		}
+	}
+
+	return SectionStat{}, false
+}
+
+// Readability returns the Flesch reading ease of the section, higher scores are easier to read. Sections without words
+// have a score of 0.
+func (ss SectionStat) Readability() float64 {
+	if ss.Words == 0 || ss.Sentences == 0 {
+		return 0
+	}
+
+	return 206.835 - 1.015*float64(ss.Words)/float64(ss.Sentences) - 84.6*float64(ss.Syllables)/float64(ss.Words)
+}
+
+// GetIssues returns the violations of the rule by the section
+func (ss SectionStat) GetIssues(rule SectionRule) []string {
+	var issues []string
+
+	if rule.MinWords > 0 && ss.Words < rule.MinWords {
+		issues = append(issues, fmt.Sprintf("section is too short: %s (%d words, minimum %d)", ss.Title, ss.Words, rule.MinWords))
+	}
+
+	if rule.MaxWords > 0 && ss.Words > rule.MaxWords {
+		issues = append(issues, fmt.Sprintf("section is too long: %s (%d words, maximum %d)", ss.Title, ss.Words, rule.MaxWords))
+	}
+
+	if rule.RequireList && ss.Lists == 0 {
+		issues = append(issues, "section does not contain a list: "+ss.Title)
+	}
+
+	if rule.MinItems > 0 && ss.ListItems < rule.MinItems {
+		issues = append(issues, fmt.Sprintf("section has too few list items: %s (%d, minimum %d)", ss.Title, ss.ListItems, rule.MinItems))
+	}
+
+	if rule.MaxItems > 0 && ss.ListItems > rule.MaxItems {
+		issues = append(issues, fmt.Sprintf("section has too many list items: %s (%d, maximum %d)", ss.Title, ss.ListItems, rule.MaxItems))
+	}
+
+	if rule.MinReadability != 0 && ss.Words > 0 && ss.Readability() < rule.MinReadability {
+		issues = append(issues, fmt.Sprintf("section is hard to read: %s (Flesch reading ease %.1f, minimum %.1f)", ss.Title, ss.Readability(), rule.MinReadability))
+	}
+
+	return issues
+}
+
+var (
+	regexWord              = regexp.MustCompile(`[\pL\pN]+(?:['’-][\pL\pN]+)*`)
+	regexSentenceEnd       = regexp.MustCompile(`[.!?]+(?:\s|$)`)
+	regexSentenceEndAtLast = regexp.MustCompile(`[.!?]+$`)
+	regexVowels            = regexp.MustCompile(`[aeiouy]+`)
+)
+
+func measureSections(sections Sections) SectionStats {
+	var stats SectionStats
+
+	for _, section := range sections {
+		stat := SectionStat{Title: section.Title}
+
+		for _, node := range section.Nodes {
+			if node.Kind == ListNode {
+				stat.Lists++
+				stat.ListItems += len(node.Children)
+			}
+		}
+
+		walkNodes(section.Nodes, func(node *Node) bool {
+			if node.Kind != ParagraphNode && node.Kind != HeadingNode {
+				return true
+			}
+
+			measureText(node.Text, &stat)
+
+			return false
+		})
+
+		stats = append(stats, stat)
+	}
+
+	return stats
+}
+
+// measureText counts the words, sentences and syllables of a paragraph or heading, every paragraph and heading ends
+// a sentence
+func measureText(text string, stat *SectionStat) {
+	prose := regexNonProse.ReplaceAllString(text, " ")
+
+	words := regexWord.FindAllString(prose, -1)
+	if len(words) == 0 {
+		return
+	}
+
+	stat.Words += len(words)
+
+	for _, word := range words {
+		stat.Syllables += syllables(word)
+	}
+
+	for _, row := range strings.Split(prose, EOL) {
+		stat.Sentences += len(regexSentenceEnd.FindAllString(row, -1))
+	}
+
+	if !regexSentenceEndAtLast.MatchString(strings.TrimSpace(prose)) {
+		stat.Sentences++
+	}
+}
+
+// syllables estimates the number of syllables of an English word by counting its vowel groups
+func syllables(word string) int {
+	word = strings.ToLower(word)
+
+	count := len(regexVowels.FindAllString(word, -1))
+
+	// silent e at the end of words, such as "make", but not "table"
+	if count > 1 && strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") {
+		count--
+	}
+
+	return max(count, 1)
+}
+
+// CheckSections adds an issue to every page with a section which violates its rule
+func (c Courses) CheckSections(config Config) {
+	for _, course := range c {
+		for _, chapter := range course.Chapters {
+			for i, page := range chapter.Pages {
+				for _, stat := range page.Content.SectionStats {
+					rule, ok := config.Sections[stat.Title]
+					if !ok {
+						continue
+					}
+
+					chapter.Pages[i].Issues = append(chapter.Pages[i].Issues, stat.GetIssues(rule)...)
+				}
+			}
+		}
+	}
+}
+
+type ReadabilityStat struct {
+	FilePath    string
+	Words       int
+	Readability float64
+}
+
+type ReadabilityStats []ReadabilityStat
+
+// ReadabilityStats returns the readability of the summaries of the pages, the hardest to read first
+func (c Courses) ReadabilityStats() ReadabilityStats {
+	var stats ReadabilityStats
+
+	for _, course := range c {
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				stat, ok := page.Content.SectionStats.Get(sectionSummary)
+				if !ok || stat.Words == 0 {
+					continue
+				}
+
+				stats = append(stats, ReadabilityStat{FilePath: page.FilePath, Words: stat.Words, Readability: stat.Readability()})
+			}
+		}
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Readability < stats[j].Readability
+	})
+
+	return stats
+}
+
+func (rs ReadabilityStats) String() string {
+	width := len("Page")
+	for _, stat := range rs {
+		width = max(width, len(stat.FilePath))
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s | %s | %s\n", column("Page", width, cliBold), column("Words", 5, cliBold), column("Flesch", 6, cliBold)))
+	sb.WriteString(strings.Repeat("-", width+1) + "+" + strings.Repeat("-", 7) + "+" + strings.Repeat("-", 8) + EOL)
+
+	for _, stat := range rs {
+		color := cliGreen
+		switch {
+		case stat.Readability < 30:
+			color = cliRed
+		case stat.Readability < 50:
+			color = cliYellow
+		}
+
+		sb.WriteString(fmt.Sprintf("%s | %s | %s\n", column(stat.FilePath, width, cliReset), column(stat.Words, 5, cliReset), column(fmt.Sprintf("%.1f", stat.Readability), 6, color)))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,171 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_measureSections(t *testing.T) {
+	const content = "## Summary\n\nThe cat sat on the mat. It was happy!\n\n- one item\n- two items\n\n```go\nfmt.Println(\"not counted\")\n```\n\n" +
+		"## Topics\n\n- `fmt.Println` basics\n  - nested\n\n## Main Video\n\n{{< youtube abc >}}\n"
+
+	// execute
+	got := measureSections(ParseDocument(content).Sections())
+
+	// verify
+	assert.Equal(t, SectionStats{
+		{Title: "summary", Words: 13, Sentences: 4, Syllables: 16, Lists: 1, ListItems: 2},
+		{Title: "topics", Words: 2, Sentences: 2, Syllables: 4, Lists: 1, ListItems: 1},
+		{Title: "main video"},
+	}, got)
+}
+
+func Test_syllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{word: "go", want: 1},
+		{word: "make", want: 1},
+		{word: "table", want: 2},
+		{word: "function", want: 2},
+		{word: "Readability", want: 5},
+		{word: "fmt", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			// execute
+			got := syllables(tt.word)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSectionStat_Readability(t *testing.T) {
+	tests := []struct {
+		name string
+		stat SectionStat
+		want float64
+	}{
+		{
+			name: "empty",
+			stat: SectionStat{},
+			want: 0,
+		},
+		{
+			name: "simple",
+			stat: SectionStat{Words: 10, Sentences: 2, Syllables: 12},
+			want: 100.24,
+		},
+		{
+			name: "hard",
+			stat: SectionStat{Words: 40, Sentences: 1, Syllables: 80},
+			want: -2.965,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := tt.stat.Readability()
+
+			// verify
+			assert.InDelta(t, tt.want, got, 0.001)
+		})
+	}
+}
+
+func TestSectionStat_GetIssues(t *testing.T) {
+	tests := []struct {
+		name string
+		stat SectionStat
+		rule SectionRule
+		want []string
+	}{
+		{
+			name: "no rules",
+			stat: SectionStat{Title: "summary"},
+			rule: SectionRule{},
+			want: nil,
+		},
+		{
+			name: "valid",
+			stat: SectionStat{Title: "summary", Words: 30, Sentences: 6, Syllables: 36, Lists: 1, ListItems: 4},
+			rule: SectionRule{MinWords: 20, MaxWords: 300, RequireList: true, MinItems: 3, MaxItems: 10, MinReadability: 50},
+			want: nil,
+		},
+		{
+			name: "too short without list",
+			stat: SectionStat{Title: "summary", Words: 12, Sentences: 2, Syllables: 14},
+			rule: SectionRule{MinWords: 20, RequireList: true, MinItems: 3},
+			want: []string{
+				"section is too short: summary (12 words, minimum 20)",
+				"section does not contain a list: summary",
+				"section has too few list items: summary (0, minimum 3)",
+			},
+		},
+		{
+			name: "too long with too many items and hard to read",
+			stat: SectionStat{Title: "topics", Words: 40, Sentences: 1, Syllables: 80, Lists: 1, ListItems: 12},
+			rule: SectionRule{MaxWords: 30, MaxItems: 10, MinReadability: 50},
+			want: []string{
+				"section is too long: topics (40 words, maximum 30)",
+				"section has too many list items: topics (12, maximum 10)",
+				"section is hard to read: topics (Flesch reading ease -3.0, minimum 50.0)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := tt.stat.GetIssues(tt.rule)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCourses_CheckSections(t *testing.T) {
+	config := Config{Sections: map[string]SectionRule{sectionTopics: {MinItems: 3}}}
+
+	courses := Courses{
+		{Title: "a1", Chapters: Chapters{{Pages: Pages{{Content: Content{
+			Body: DefaultBody{},
+			SectionStats: SectionStats{
+				{Title: sectionSummary, Words: 3, Sentences: 1, Syllables: 3},
+				{Title: sectionTopics, Words: 4, Sentences: 2, Syllables: 5, Lists: 1, ListItems: 2},
+			},
+		}}}}}},
+	}
+
+	// execute
+	courses.CheckSections(config)
+
+	// verify
+	assert.Equal(t, []string{"section has too few list items: topics (2, minimum 3)"}, courses[0].Chapters[0].Pages[0].Issues)
+}
+
+func TestCourses_ReadabilityStats(t *testing.T) {
+	courses := Courses{
+		{Title: "a1", Chapters: Chapters{{Pages: Pages{
+			{FilePath: "easy.md", Content: Content{SectionStats: SectionStats{{Title: sectionSummary, Words: 10, Sentences: 2, Syllables: 12}}}},
+			{FilePath: "empty.md", Content: Content{SectionStats: SectionStats{{Title: sectionSummary}}}},
+			{FilePath: "hard.md", Content: Content{SectionStats: SectionStats{{Title: sectionSummary, Words: 40, Sentences: 1, Syllables: 80}}}},
+			{FilePath: "topics.md", Content: Content{SectionStats: SectionStats{{Title: sectionTopics, Words: 10, Sentences: 1, Syllables: 10}}}},
+		}}}},
+	}
+
+	// execute
+	got := courses.ReadabilityStats()
+
+	// verify
+	require.Len(t, got, 2)
+	assert.Equal(t, "hard.md", got[0].FilePath)
+	assert.Equal(t, 40, got[0].Words)
+	assert.Equal(t, "easy.md", got[1].FilePath)
+	assert.InDelta(t, 100.24, got[1].Readability, 0.001)
+}
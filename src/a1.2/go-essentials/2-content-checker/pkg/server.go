@@ -0,0 +1,474 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SiteCrawler crawls and checks the whole site
+type SiteCrawler func() (Courses, error)
+
+// SiteScanner returns the modification times of the files the checks depend on, keyed by their paths
+type SiteScanner func() (map[string]time.Time, error)
+
+// APIServer keeps the catalog of the site in memory and serves it as JSON. The site is crawled again whenever a scan
+// finds added, removed or modified files, site wide checks make it necessary to crawl every file in that case.
+type APIServer struct {
+	crawl SiteCrawler
+	scan  SiteScanner
+
+	lock      sync.RWMutex
+	catalog   Catalog
+	modTimes  map[string]time.Time
+	crawledAt time.Time
+}
+
+func NewAPIServer(crawl SiteCrawler, scan SiteScanner) *APIServer {
+	return &APIServer{
+		crawl:   crawl,
+		scan:    scan,
+		catalog: Catalog{SchemaVersion: CatalogSchemaVersion, Courses: []CourseExport{}},
+	}
+}
+
+// Refresh scans the site and crawls it again if any of its files changed since the last crawl, it returns whether the
+// site was crawled
+func (s *APIServer) Refresh() (bool, error) {
+	modTimes, err := s.scan()
+	if err != nil {
+		return false, fmt.Errorf("cannot scan site: %w", err)
+	}
+
+	s.lock.RLock()
+	changed := s.crawledAt.IsZero() || !sameModTimes(s.modTimes, modTimes)
+	s.lock.RUnlock()
+
+	if !changed {
+		return false, nil
+	}
+
+	courses, err := s.crawl()
+	if err != nil {
+		return false, fmt.Errorf("cannot crawl site: %w", err)
+	}
+
+	catalog := courses.Export()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.catalog = catalog
+	s.modTimes = modTimes
+	s.crawledAt = time.Now()
+
+	return true, nil
+}
+
+func sameModTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for filePath, modTime := range a {
+		if other, ok := b[filePath]; !ok || !other.Equal(modTime) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Poll refreshes the site in the given interval until the context is done, failed refreshes are reported and retried
+// in the next interval
+func (s *APIServer) Poll(ctx context.Context, interval time.Duration, report func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Refresh(); err != nil {
+				report(err)
+			}
+		}
+	}
+}
+
+func (s *APIServer) snapshot() (Catalog, time.Time) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.catalog, s.crawledAt
+}
+
+// Handler returns the handler of the JSON endpoints:
+//
+//	GET  /api/courses                  courses, filters: state
+//	GET  /api/courses/{course}         a course with its chapters and pages
+//	GET  /api/chapters                 chapters, filters: course, state
+//	GET  /api/pages                    pages, filters: course, chapter, kind, state, calculatedState, audience, tag,
+//	                                   language (translations of the language instead of source pages), hasIssues
+//	GET  /api/issues                   issues, filters: course, chapter, filePath, q (case-insensitive substring)
+//	GET  /api/stats                    page counts, filters: course
+//	POST /api/refresh                  crawls the site again if any of its files changed
+func (s *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/courses", s.handleCourses)
+	mux.HandleFunc("GET /api/courses/{course}", s.handleCourse)
+	mux.HandleFunc("GET /api/chapters", s.handleChapters)
+	mux.HandleFunc("GET /api/pages", s.handlePages)
+	mux.HandleFunc("GET /api/issues", s.handleIssues)
+	mux.HandleFunc("GET /api/stats", s.handleStats)
+	mux.HandleFunc("POST /api/refresh", s.handleRefresh)
+
+	return mux
+}
+
+type CourseSummary struct {
+	Title    string   `json:"title"`
+	State    State    `json:"state"`
+	Chapters int      `json:"chapters"`
+	Pages    int      `json:"pages"`
+	Issues   []string `json:"issues"`
+}
+
+type ChapterSummary struct {
+	Course string `json:"course"`
+	Title  string `json:"title"`
+	State  State  `json:"state"`
+	Pages  int    `json:"pages"`
+	// IssueCount is the number of issues of the pages of the chapter
+	IssueCount int `json:"issueCount"`
+}
+
+type APIPage struct {
+	Course  string `json:"course"`
+	Chapter string `json:"chapter"`
+	PageExport
+}
+
+type APIIssue struct {
+	Course  string `json:"course"`
+	Chapter string `json:"chapter,omitempty"`
+	// FilePath is omitted for the issues of a course without an index page
+	FilePath string `json:"filePath,omitempty"`
+	Issue    string `json:"issue"`
+}
+
+type APIStats struct {
+	CrawledAt       time.Time `json:"crawledAt"`
+	Courses         int       `json:"courses"`
+	Chapters        int       `json:"chapters"`
+	Pages           int       `json:"pages"`
+	Translations    int       `json:"translations"`
+	PagesWithIssues int       `json:"pagesWithIssues"`
+	Issues          int       `json:"issues"`
+	// States counts the pages by the state set in their front matter
+	States           map[State]int    `json:"states"`
+	CalculatedStates map[State]int    `json:"calculatedStates"`
+	Kinds            map[PageKind]int `json:"kinds"`
+	Audiences        map[Audience]int `json:"audiences"`
+	// Languages counts the translations by their languages
+	Languages map[string]int `json:"languages"`
+}
+
+type refreshResponse struct {
+	Changed   bool      `json:"changed"`
+	CrawledAt time.Time `json:"crawledAt"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// matches reports whether a query filter is not set or is equal to the value
+func matches(query string, value string) bool {
+	return query == "" || query == value
+}
+
+func (s *APIServer) handleCourses(w http.ResponseWriter, r *http.Request) {
+	catalog, _ := s.snapshot()
+	query := r.URL.Query()
+
+	result := []CourseSummary{}
+
+	for _, course := range catalog.Courses {
+		if !matches(query.Get("state"), string(course.State)) {
+			continue
+		}
+
+		summary := CourseSummary{Title: course.Title, State: course.State, Chapters: len(course.Chapters), Issues: course.Issues}
+		for _, chapter := range course.Chapters {
+			summary.Pages += len(chapter.Pages)
+		}
+
+		result = append(result, summary)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *APIServer) handleCourse(w http.ResponseWriter, r *http.Request) {
+	catalog, _ := s.snapshot()
+
+	for _, course := range catalog.Courses {
+		if course.Title == r.PathValue("course") {
+			writeJSON(w, http.StatusOK, course)
+
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "course not found: "+r.PathValue("course"))
+}
+
+func (s *APIServer) handleChapters(w http.ResponseWriter, r *http.Request) {
+	catalog, _ := s.snapshot()
+	query := r.URL.Query()
+
+	result := []ChapterSummary{}
+
+	for _, course := range catalog.Courses {
+		if !matches(query.Get("course"), course.Title) {
+			continue
+		}
+
+		for _, chapter := range course.Chapters {
+			if !matches(query.Get("state"), string(chapter.State)) {
+				continue
+			}
+
+			summary := ChapterSummary{Course: course.Title, Title: chapter.Title, State: chapter.State, Pages: len(chapter.Pages)}
+			for _, page := range chapter.Pages {
+				summary.IssueCount += len(page.Issues)
+			}
+
+			result = append(result, summary)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *APIServer) handlePages(w http.ResponseWriter, r *http.Request) {
+	catalog, _ := s.snapshot()
+	query := r.URL.Query()
+
+	var hasIssues *bool
+
+	if raw := query.Get("hasIssues"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid hasIssues: "+raw)
+
+			return
+		}
+
+		hasIssues = &value
+	}
+
+	result := []APIPage{}
+
+	for _, course := range catalog.Courses {
+		if !matches(query.Get("course"), course.Title) {
+			continue
+		}
+
+		for _, chapter := range course.Chapters {
+			if !matches(query.Get("chapter"), chapter.Title) {
+				continue
+			}
+
+			for _, page := range chapter.Pages {
+				candidates := []PageExport{page}
+				if language := query.Get("language"); language != "" {
+					candidates = candidates[:0]
+
+					for _, translation := range page.Translations {
+						if translation.Language == language {
+							candidates = append(candidates, translation)
+						}
+					}
+				}
+
+				for _, candidate := range candidates {
+					if !pageMatches(candidate, query.Get, hasIssues) {
+						continue
+					}
+
+					result = append(result, APIPage{Course: course.Title, Chapter: chapter.Title, PageExport: candidate})
+				}
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func pageMatches(page PageExport, get func(key string) string, hasIssues *bool) bool {
+	if !matches(get("kind"), string(page.Kind)) ||
+		!matches(get("state"), string(page.State)) ||
+		!matches(get("calculatedState"), string(page.CalculatedState)) ||
+		!matches(get("audience"), string(page.Audience)) {
+		return false
+	}
+
+	if tag := get("tag"); tag != "" && !contains(page.Tags, tag) {
+		return false
+	}
+
+	if hasIssues != nil && *hasIssues != (len(page.Issues) > 0) {
+		return false
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *APIServer) handleIssues(w http.ResponseWriter, r *http.Request) {
+	catalog, _ := s.snapshot()
+	query := r.URL.Query()
+	search := strings.ToLower(query.Get("q"))
+
+	result := []APIIssue{}
+
+	add := func(issue APIIssue) {
+		if !matches(query.Get("filePath"), issue.FilePath) || !strings.Contains(strings.ToLower(issue.Issue), search) {
+			return
+		}
+
+		result = append(result, issue)
+	}
+
+	for _, course := range catalog.Courses {
+		if !matches(query.Get("course"), course.Title) {
+			continue
+		}
+
+		if query.Get("chapter") == "" {
+			filePath := ""
+			if course.Index != nil {
+				filePath = course.Index.FilePath
+			}
+
+			for _, issue := range course.Issues {
+				add(APIIssue{Course: course.Title, FilePath: filePath, Issue: issue})
+			}
+
+			for _, translation := range course.Translations {
+				for _, issue := range translation.Issues {
+					add(APIIssue{Course: course.Title, FilePath: translation.FilePath, Issue: issue})
+				}
+			}
+		}
+
+		for _, chapter := range course.Chapters {
+			if !matches(query.Get("chapter"), chapter.Title) {
+				continue
+			}
+
+			for _, page := range chapter.Pages {
+				for _, issue := range page.Issues {
+					add(APIIssue{Course: course.Title, Chapter: chapter.Title, FilePath: page.FilePath, Issue: issue})
+				}
+
+				for _, translation := range page.Translations {
+					for _, issue := range translation.Issues {
+						add(APIIssue{Course: course.Title, Chapter: chapter.Title, FilePath: translation.FilePath, Issue: issue})
+					}
+				}
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *APIServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	catalog, crawledAt := s.snapshot()
+	query := r.URL.Query()
+
+	stats := APIStats{
+		CrawledAt:        crawledAt,
+		States:           map[State]int{},
+		CalculatedStates: map[State]int{},
+		Kinds:            map[PageKind]int{},
+		Audiences:        map[Audience]int{},
+		Languages:        map[string]int{},
+	}
+
+	for _, course := range catalog.Courses {
+		if !matches(query.Get("course"), course.Title) {
+			continue
+		}
+
+		stats.Courses++
+		stats.Chapters += len(course.Chapters)
+		stats.Issues += len(course.Issues)
+
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				stats.Pages++
+				stats.States[page.State]++
+				stats.CalculatedStates[page.CalculatedState]++
+				stats.Kinds[page.Kind]++
+				stats.Audiences[page.Audience]++
+				stats.Issues += len(page.Issues)
+
+				if len(page.Issues) > 0 {
+					stats.PagesWithIssues++
+				}
+
+				for _, translation := range page.Translations {
+					stats.Translations++
+					stats.Languages[translation.Language]++
+					stats.Issues += len(translation.Issues)
+				}
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *APIServer) handleRefresh(w http.ResponseWriter, _ *http.Request) {
+	changed, err := s.Refresh()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	_, crawledAt := s.snapshot()
+
+	writeJSON(w, http.StatusOK, refreshResponse{Changed: changed, CrawledAt: crawledAt})
+}
@@ -0,0 +1,239 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func apiTestServer(t *testing.T) *APIServer {
+	t.Helper()
+
+	courses := exportTestCourses(t)
+
+	server := NewAPIServer(
+		func() (Courses, error) { return courses, nil },
+		func() (map[string]time.Time, error) { return map[string]time.Time{}, nil },
+	)
+
+	_, err := server.Refresh()
+	require.NoError(t, err)
+
+	return server
+}
+
+func TestAPIServer_Handler(t *testing.T) {
+	server := apiTestServer(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "courses",
+			method:     http.MethodGet,
+			target:     "/api/courses",
+			wantStatus: http.StatusOK,
+			want:       `[{"title":"a1","state":"incomplete","chapters":1,"pages":2,"issues":["translation is missing: pl"]}]`,
+		},
+		{
+			name:       "courses filtered by state",
+			method:     http.MethodGet,
+			target:     "/api/courses?state=complete",
+			wantStatus: http.StatusOK,
+			want:       `[]`,
+		},
+		{
+			name:       "unknown course",
+			method:     http.MethodGet,
+			target:     "/api/courses/b1",
+			wantStatus: http.StatusNotFound,
+			want:       `{"error":"course not found: b1"}`,
+		},
+		{
+			name:       "chapters",
+			method:     http.MethodGet,
+			target:     "/api/chapters?course=a1",
+			wantStatus: http.StatusOK,
+			want:       `[{"course":"a1","title":"go","state":"incomplete","pages":2,"issueCount":3}]`,
+		},
+		{
+			name:       "issues",
+			method:     http.MethodGet,
+			target:     "/api/issues?chapter=go&q=CODE",
+			wantStatus: http.StatusOK,
+			want:       `[{"course":"a1","chapter":"go","filePath":"content/a1/go/10-foo.md","issue":"line 29: code block without a language tag"}]`,
+		},
+		{
+			name:       "invalid filter",
+			method:     http.MethodGet,
+			target:     "/api/pages?hasIssues=maybe",
+			wantStatus: http.StatusBadRequest,
+			want:       `{"error":"invalid hasIssues: maybe"}`,
+		},
+		{
+			name:       "refresh without changes",
+			method:     http.MethodPost,
+			target:     "/api/refresh",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			target:     "/api/courses",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			// execute
+			server.Handler().ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.target, nil))
+
+			// verify
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+
+			if tt.want != "" {
+				assert.JSONEq(t, tt.want, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIServer_Handler_Pages(t *testing.T) {
+	server := apiTestServer(t)
+
+	tests := []struct {
+		name      string
+		target    string
+		wantPaths []string
+	}{
+		{
+			name:      "all source pages",
+			target:    "/api/pages",
+			wantPaths: []string{"content/a1/go/_index.md", "content/a1/go/10-foo.md"},
+		},
+		{
+			name:      "kind and tag",
+			target:    "/api/pages?kind=page&tag=go",
+			wantPaths: []string{"content/a1/go/10-foo.md"},
+		},
+		{
+			name:      "unknown tag",
+			target:    "/api/pages?tag=rust",
+			wantPaths: []string{},
+		},
+		{
+			name:      "translations",
+			target:    "/api/pages?language=pl",
+			wantPaths: []string{"content/a1/go/10-foo.pl.md"},
+		},
+		{
+			name:      "calculated state",
+			target:    "/api/pages?chapter=go&kind=page&calculatedState=incomplete&hasIssues=true",
+			wantPaths: []string{"content/a1/go/10-foo.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			// execute
+			server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			// verify
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var pages []APIPage
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &pages))
+
+			paths := []string{}
+			for _, page := range pages {
+				assert.Equal(t, "a1", page.Course)
+				assert.Equal(t, "go", page.Chapter)
+
+				paths = append(paths, page.FilePath)
+			}
+
+			assert.Equal(t, tt.wantPaths, paths)
+		})
+	}
+}
+
+func TestAPIServer_Handler_Stats(t *testing.T) {
+	server := apiTestServer(t)
+	recorder := httptest.NewRecorder()
+
+	// execute
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/stats?course=a1", nil))
+
+	// verify
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got APIStats
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+
+	assert.Equal(t, 1, got.Courses)
+	assert.Equal(t, 1, got.Chapters)
+	assert.Equal(t, 2, got.Pages)
+	assert.Equal(t, 1, got.Translations)
+	assert.Equal(t, map[PageKind]int{IndexPage: 1, DefaultPage: 1}, got.Kinds)
+	assert.Equal(t, map[string]int{"pl": 1}, got.Languages)
+	assert.False(t, got.CrawledAt.IsZero())
+}
+
+func TestAPIServer_Refresh(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("crawls only when files changed", func(t *testing.T) {
+		crawls := 0
+		modTimes := map[string]time.Time{"content/a1/go/10-foo.md": modTime}
+
+		server := NewAPIServer(
+			func() (Courses, error) { crawls++; return nil, nil },
+			func() (map[string]time.Time, error) { return modTimes, nil },
+		)
+
+		// execute
+		first, err1 := server.Refresh()
+		second, err2 := server.Refresh()
+
+		modTimes = map[string]time.Time{"content/a1/go/10-foo.md": modTime.Add(time.Second)}
+		third, err3 := server.Refresh()
+
+		modTimes = map[string]time.Time{"content/a1/go/10-foo.md": modTime.Add(time.Second), "content/a1/go/20-bar.md": modTime}
+		fourth, err4 := server.Refresh()
+
+		// verify
+		require.NoError(t, errors.Join(err1, err2, err3, err4))
+		assert.Equal(t, []bool{true, false, true, true}, []bool{first, second, third, fourth})
+		assert.Equal(t, 3, crawls)
+	})
+
+	t.Run("failed crawl keeps the previous catalog", func(t *testing.T) {
+		server := apiTestServer(t)
+
+		server.scan = func() (map[string]time.Time, error) { return map[string]time.Time{"foo.md": modTime}, nil }
+		server.crawl = func() (Courses, error) { return nil, errors.New("cannot parse markdown") }
+
+		// execute
+		changed, err := server.Refresh()
+
+		// verify
+		require.Error(t, err)
+		assert.False(t, changed)
+
+		catalog, _ := server.snapshot()
+		assert.Len(t, catalog.Courses, 1)
+	})
+}
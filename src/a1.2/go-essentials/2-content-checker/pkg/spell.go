@@ -0,0 +1,605 @@
+package pkg
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const ProjectDictionaryFileName = "dictionary.txt"
+
+const maxSpellingSuggestions = 3
+
+var (
+	//go:embed dictionary/en_US.dic
+	englishWords string
+	//go:embed dictionary/en_US.aff
+	englishAffixes string
+)
+
+// Dictionary is a set of correctly spelled words
+type Dictionary struct {
+	words map[string]struct{}
+	// noSuggest contains the words which are accepted but never suggested (e.g. offensive words)
+	noSuggest map[string]struct{}
+}
+
+func newDictionary() Dictionary {
+	return Dictionary{
+		words:     make(map[string]struct{}),
+		noSuggest: make(map[string]struct{}),
+	}
+}
+
+func (d Dictionary) add(word string) {
+	d.words[strings.Replace(word, "’", "'", -1)] = struct{}{}
+}
+
+func (d Dictionary) Contains(word string) bool {
+	_, ok := d.words[word]
+
+	return ok
+}
+
+// ParseDictionary parses a project dictionary file, each line contains a word. Empty lines and lines starting with #
+// are ignored.
+func ParseDictionary(raw string) Dictionary {
+	dictionary := newDictionary()
+
+	for _, row := range strings.Split(strings.Replace(raw, "\r\n", EOL, -1), EOL) {
+		row = strings.TrimSpace(row)
+		if row == "" || strings.HasPrefix(row, "#") {
+			continue
+		}
+
+		dictionary.add(row)
+	}
+
+	return dictionary
+}
+
+// EnglishDictionary returns the bundled English dictionary
+func EnglishDictionary() (Dictionary, error) {
+	return parseHunspell(englishWords, englishAffixes)
+}
+
+type affixRule struct {
+	strip     string
+	add       string
+	condition *regexp.Regexp
+}
+
+type affix struct {
+	prefix bool
+	cross  bool
+	rules  []affixRule
+}
+
+func (a affix) apply(word string, rule affixRule) (string, bool) {
+	if !rule.condition.MatchString(word) {
+		return "", false
+	}
+
+	if a.prefix {
+		if !strings.HasPrefix(word, rule.strip) {
+			return "", false
+		}
+
+		return rule.add + word[len(rule.strip):], true
+	}
+
+	if !strings.HasSuffix(word, rule.strip) {
+		return "", false
+	}
+
+	return word[:len(word)-len(rule.strip)] + rule.add, true
+}
+
+// parseAffixes parses the prefix and suffix rules of a Hunspell affix file and returns the NOSUGGEST flag
+func parseAffixes(raw string) (map[rune]affix, rune, error) {
+	affixes := make(map[rune]affix)
+
+	var noSuggest rune
+
+	for i, row := range strings.Split(strings.Replace(raw, "\r\n", EOL, -1), EOL) {
+		fields := strings.Fields(row)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "NOSUGGEST" && len(fields) > 1 {
+			noSuggest, _ = utf8.DecodeRuneInString(fields[1])
+
+			continue
+		}
+
+		if fields[0] != "PFX" && fields[0] != "SFX" {
+			continue
+		}
+
+		if len(fields) < 4 {
+			return nil, 0, fmt.Errorf("invalid affix on line %d", i+1)
+		}
+
+		flag, _ := utf8.DecodeRuneInString(fields[1])
+
+		a, ok := affixes[flag]
+		if !ok {
+			// header: PFX flag cross_product number_of_rules
+			affixes[flag] = affix{prefix: fields[0] == "PFX", cross: fields[2] == "Y"}
+
+			continue
+		}
+
+		if len(fields) < 5 {
+			return nil, 0, fmt.Errorf("invalid affix rule on line %d", i+1)
+		}
+
+		rule := affixRule{strip: fields[2], add: fields[3]}
+		if rule.strip == "0" {
+			rule.strip = ""
+		}
+
+		// continuation classes are not supported
+		rule.add, _, _ = strings.Cut(rule.add, "/")
+		if rule.add == "0" {
+			rule.add = ""
+		}
+
+		pattern := "(?:" + fields[4] + ")$"
+		if a.prefix {
+			pattern = "^(?:" + fields[4] + ")"
+		}
+
+		condition, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid affix condition on line %d: %w", i+1, err)
+		}
+
+		rule.condition = condition
+
+		a.rules = append(a.rules, rule)
+		affixes[flag] = a
+	}
+
+	return affixes, noSuggest, nil
+}
+
+// parseHunspell expands the words of a Hunspell dictionary using the rules of its affix file
+func parseHunspell(words, affixes string) (Dictionary, error) {
+	rules, noSuggest, err := parseAffixes(affixes)
+	if err != nil {
+		return Dictionary{}, err
+	}
+
+	dictionary := newDictionary()
+
+	for i, row := range strings.Split(strings.Replace(words, "\r\n", EOL, -1), EOL) {
+		fields := strings.Fields(row)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// the first line contains the number of words
+		if _, err := strconv.Atoi(fields[0]); i == 0 && err == nil {
+			continue
+		}
+
+		word, flags, _ := strings.Cut(fields[0], "/")
+
+		forms := []string{word}
+
+		var crossForms []string
+
+		for _, flag := range flags {
+			a, ok := rules[flag]
+			if !ok || a.prefix {
+				continue
+			}
+
+			for _, rule := range a.rules {
+				if form, ok := a.apply(word, rule); ok {
+					forms = append(forms, form)
+
+					if a.cross {
+						crossForms = append(crossForms, form)
+					}
+				}
+			}
+		}
+
+		for _, flag := range flags {
+			a, ok := rules[flag]
+			if !ok || !a.prefix {
+				continue
+			}
+
+			for _, rule := range a.rules {
+				if form, ok := a.apply(word, rule); ok {
+					forms = append(forms, form)
+				}
+
+				if !a.cross {
+					continue
+				}
+
+				for _, crossForm := range crossForms {
+					if form, ok := a.apply(crossForm, rule); ok {
+						forms = append(forms, form)
+					}
+				}
+			}
+		}
+
+		for _, form := range forms {
+			dictionary.add(form)
+
+			if noSuggest != 0 && strings.ContainsRune(flags, noSuggest) {
+				dictionary.noSuggest[form] = struct{}{}
+			}
+		}
+	}
+
+	return dictionary, nil
+}
+
+type SpellingIssue struct {
+	Word        string
+	Position    Position
+	Suggestions []string
+}
+
+func (si SpellingIssue) String() string {
+	if len(si.Suggestions) == 0 {
+		return fmt.Sprintf("line %d, column %d: unknown word: %s", si.Position.Line, si.Position.Column, si.Word)
+	}
+
+	return fmt.Sprintf("line %d, column %d: unknown word: %s (did you mean: %s?)",
+		si.Position.Line, si.Position.Column, si.Word, strings.Join(si.Suggestions, ", "))
+}
+
+// SpellChecker checks the prose of markdown files against a list of dictionaries
+type SpellChecker struct {
+	dictionaries []Dictionary
+	suggestions  map[string][]string
+	// suggestable contains the words which can be suggested grouped by their number of runes, built on the first
+	// suggestion
+	suggestable map[int][]suggestableWord
+}
+
+type suggestableWord struct {
+	word         string
+	lower        []rune
+	spellChecked bool
+}
+
+func NewSpellChecker(dictionaries ...Dictionary) *SpellChecker {
+	return &SpellChecker{
+		dictionaries: dictionaries,
+		suggestions:  make(map[string][]string),
+	}
+}
+
+func (sc *SpellChecker) contains(word string, page Dictionary) bool {
+	candidates := []string{word, strings.ToLower(word)}
+
+	// possessive form of words missing from the dictionaries, such as names
+	if base, ok := strings.CutSuffix(word, "'s"); ok {
+		candidates = append(candidates, base, strings.ToLower(base))
+	}
+
+	for _, candidate := range candidates {
+		if page.Contains(candidate) {
+			return true
+		}
+
+		for _, dictionary := range sc.dictionaries {
+			if dictionary.Contains(candidate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Suggest returns the words closest to the given one by edit distance. Swapped letters, lowercase words and matching
+// first letters are preferred on equal distances.
+func (sc *SpellChecker) Suggest(word string) []string {
+	if suggestions, ok := sc.suggestions[word]; ok {
+		return suggestions
+	}
+
+	lower := strings.ToLower(word)
+	length := utf8.RuneCountInString(lower)
+
+	maxDistance := 1
+	if length > 4 {
+		maxDistance = 2
+	}
+
+	type candidate struct {
+		word      string
+		distance  int
+		swapped   bool
+		lowercase bool
+		sameFirst bool
+	}
+
+	var candidates []candidate
+
+	runes := []rune(lower)
+	spellChecked := isSpellChecked(word)
+
+	for size := length - maxDistance; size <= length+maxDistance; size++ {
+		for _, known := range sc.suggestableWords()[size] {
+			// acronyms are rarely the intended word of a regular word
+			if !known.spellChecked && spellChecked {
+				continue
+			}
+
+			distance := editDistance(runes, known.lower, maxDistance)
+			if distance > maxDistance {
+				continue
+			}
+
+			candidates = append(candidates, candidate{
+				word:      known.word,
+				distance:  distance,
+				swapped:   sortedRunes(runes) == sortedRunes(known.lower),
+				lowercase: string(known.lower) == known.word,
+				sameFirst: known.lower[0] == runes[0],
+			})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+
+		switch {
+		case a.distance != b.distance:
+			return a.distance < b.distance
+		case a.swapped != b.swapped:
+			return a.swapped
+		case a.lowercase != b.lowercase:
+			return a.lowercase
+		case a.sameFirst != b.sameFirst:
+			return a.sameFirst
+		}
+
+		return a.word < b.word
+	})
+
+	var suggestions []string
+
+	found := make(map[string]struct{}, len(candidates))
+	for _, c := range candidates {
+		if _, ok := found[c.word]; ok {
+			continue
+		}
+
+		found[c.word] = struct{}{}
+		suggestions = append(suggestions, c.word)
+
+		if len(suggestions) == maxSpellingSuggestions {
+			break
+		}
+	}
+
+	sc.suggestions[word] = suggestions
+
+	return suggestions
+}
+
+// suggestableWords returns the words of the dictionaries which can be suggested grouped by their number of runes,
+// possessive forms and the words marked as not to suggest are left out
+func (sc *SpellChecker) suggestableWords() map[int][]suggestableWord {
+	if sc.suggestable != nil {
+		return sc.suggestable
+	}
+
+	sc.suggestable = make(map[int][]suggestableWord)
+
+	for _, dictionary := range sc.dictionaries {
+		for known := range dictionary.words {
+			if _, ok := dictionary.noSuggest[known]; ok || strings.HasSuffix(known, "'s") {
+				continue
+			}
+
+			lower := []rune(strings.ToLower(known))
+
+			sc.suggestable[len(lower)] = append(sc.suggestable[len(lower)], suggestableWord{
+				word:         known,
+				lower:        lower,
+				spellChecked: isSpellChecked(known),
+			})
+		}
+	}
+
+	return sc.suggestable
+}
+
+func sortedRunes(word []rune) string {
+	runes := append([]rune{}, word...)
+	sort.Slice(runes, func(i, j int) bool {
+		return runes[i] < runes[j]
+	})
+
+	return string(runes)
+}
+
+// editDistance returns the minimum number of single character edits needed to turn a into b, swapping two neighbouring
+// characters counts as a single edit. The calculation stops as soon as the distance exceeds limit, limit+1 is returned
+// then.
+func editDistance(a, b []rune, limit int) int {
+	if len(a)-len(b) > limit || len(b)-len(a) > limit {
+		return limit + 1
+	}
+
+	// only the last three rows are needed, swaps look two rows back. The rows of short words fit on the stack.
+	var buffer [96]int
+
+	size := len(b) + 1
+
+	rows := buffer[:]
+	if 3*size > len(buffer) {
+		rows = make([]int, 3*size)
+	}
+
+	previous, last, current := rows[:size], rows[size:2*size], rows[2*size:3*size]
+
+	for j := range last {
+		last[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(last[j]+1, current[j-1]+1, last[j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				current[j] = min(current[j], previous[j-2]+1)
+			}
+
+			rowMin = min(rowMin, current[j])
+		}
+
+		if rowMin > limit {
+			return limit + 1
+		}
+
+		previous, last, current = last, current, previous
+	}
+
+	return min(last[len(b)], limit+1)
+}
+
+// Check returns the unknown words of the title and the prose of the body of a markdown file. Code, comments,
+// shortcodes, URLs and link destinations are skipped. Words listed in the spellIgnore front matter are accepted.
+func (sc *SpellChecker) Check(rawContent string) ([]SpellingIssue, error) {
+	strContent := strings.Replace(rawContent, "\r\n", EOL, -1)
+
+	header, _, err := splitMarkdown(strContent)
+	if err != nil {
+		return nil, fmt.Errorf("markdown header could not be extracted, err: %w", err)
+	}
+
+	page := newDictionary()
+	for _, word := range getHeaderValues(header, "spellIgnore", nil) {
+		page.add(word)
+	}
+
+	document := ParseDocument(strContent)
+
+	var issues []SpellingIssue
+
+	// the title is the only prose in the front matter
+	for i := 1; i < document.bodyStart-1; i++ {
+		matches := regexHeader.FindStringSubmatchIndex(document.rows[i])
+		if matches == nil || document.rows[i][matches[2]:matches[3]] != "title" {
+			continue
+		}
+
+		line := sourceLine{text: document.rows[i][matches[4]:matches[5]], number: i + 1, column: matches[4] + 1}
+		issues = append(issues, sc.checkLine(line, page)...)
+	}
+
+	walkNodes(document.Nodes, func(node *Node) bool {
+		if node.Kind != HeadingNode && node.Kind != ParagraphNode {
+			return true
+		}
+
+		for number := node.Start.Line; number <= node.EndLine; number++ {
+			issues = append(issues, sc.checkLine(sourceLine{text: document.rows[number-1], number: number, column: 1}, page)...)
+		}
+
+		return false
+	})
+
+	return issues, nil
+}
+
+var regexNonProse = regexp.MustCompile(strings.Join([]string{
+	"`+[^`]*`+",                     // inline code
+	`{{[<%].*?[>%]}}`,               // shortcodes
+	`\]\([^)]*\)`,                   // link destinations
+	`^\s*\[[^\]]+\]:\s*\S+`,         // link reference definitions
+	`(?:https?|ftp)://\S+|www\.\S+`, // URLs
+	`<[^>\s][^>]*>`,                 // HTML tags and autolinks
+	`\S+@\S+\.\S+`,                  // e-mail addresses
+}, "|"))
+
+var regexProseChunk = regexp.MustCompile(`\S+`)
+
+func (sc *SpellChecker) checkLine(line sourceLine, page Dictionary) []SpellingIssue {
+	var issues []SpellingIssue
+
+	text := []byte(line.text)
+	for _, loc := range regexNonProse.FindAllIndex(text, -1) {
+		for i := loc[0]; i < loc[1]; i++ {
+			text[i] = ' '
+		}
+	}
+
+	for _, loc := range regexProseChunk.FindAllIndex(text, -1) {
+		start, end := loc[0], loc[1]
+
+		// trim punctuation and markup around the word
+		start += strings.IndexFunc(string(text[start:end]), unicode.IsLetter)
+		if start < loc[0] {
+			continue
+		}
+
+		end = start + strings.LastIndexFunc(string(text[start:end]), unicode.IsLetter) + 1
+
+		chunk := strings.Replace(string(text[start:end]), "’", "'", -1)
+
+		// identifiers, paths, numbers and the like are not prose
+		if strings.IndexFunc(chunk, func(r rune) bool { return !unicode.IsLetter(r) && r != '\'' && r != '-' }) != -1 {
+			continue
+		}
+
+		offset := 0
+		for _, word := range strings.Split(chunk, "-") {
+			position := line.position(start + offset)
+			offset += len(word) + 1
+
+			if !isSpellChecked(word) || sc.contains(word, page) {
+				continue
+			}
+
+			issues = append(issues, SpellingIssue{Word: word, Position: position, Suggestions: sc.Suggest(word)})
+		}
+	}
+
+	return issues
+}
+
+// isSpellChecked returns false for single letters, acronyms and words with capitals inside (e.g. JavaScript)
+func isSpellChecked(word string) bool {
+	word = strings.Trim(word, "'")
+
+	if utf8.RuneCountInString(word) < 2 {
+		return false
+	}
+
+	for i, r := range word {
+		if i > 0 && unicode.IsUpper(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnglishDictionary(t *testing.T) {
+	// execute
+	dictionary, err := EnglishDictionary()
+
+	// verify
+	require.NoError(t, err)
+
+	for _, word := range []string{"go", "goes", "going", "function", "functions", "rerun", "unhappiness", "Google", "don't"} {
+		assert.True(t, dictionary.Contains(word), word)
+	}
+
+	for _, word := range []string{"funtion", "goed", "gogle"} {
+		assert.False(t, dictionary.Contains(word), word)
+	}
+}
+
+func Test_parseHunspell(t *testing.T) {
+	const affixes = `NOSUGGEST !
+
+PFX A Y 1
+PFX A   0     re         .
+
+SFX S Y 2
+SFX S   y     ies        [^aeiou]y
+SFX S   0     s          [^y]
+
+SFX D N 1
+SFX D   0     ed         .
+`
+
+	// execute
+	got, err := parseHunspell("4\ncopy/SA\nload/DA\nword\ndarn/!", affixes)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{
+		"copy": {}, "copies": {}, "recopy": {}, "recopies": {},
+		"load": {}, "loaded": {}, "reload": {},
+		"word": {},
+		"darn": {},
+	}, got.words)
+	assert.Equal(t, map[string]struct{}{"darn": {}}, got.noSuggest)
+}
+
+func TestSpellChecker_Check(t *testing.T) {
+	english, err := EnglishDictionary()
+	require.NoError(t, err)
+
+	checker := NewSpellChecker(english, ParseDictionary("# project words\ngoroutine\ngoroutines\n"))
+
+	tests := []struct {
+		name       string
+		rawContent string
+		want       []SpellingIssue
+	}{
+		{
+			name:       "correct",
+			rawContent: "+++\ntitle = \"Goroutines in Go\"\n+++\n\n## Summary\n\n- Goroutines are cheap, they're **lightweight** threads.\n",
+			want:       nil,
+		},
+		{
+			name:       "title and body",
+			rawContent: "+++\ntitle = \"Concurency in Go\"\nslug = \"concurency\"\n+++\n\n## Summary\n\nChanels connect goroutines.\n",
+			want: []SpellingIssue{
+				{Word: "Concurency", Position: Position{Line: 2, Column: 10}, Suggestions: []string{"concurrency", "concurrence", "congruency"}},
+				{Word: "Chanels", Position: Position{Line: 8, Column: 1}, Suggestions: []string{"chancels", "channels", "chapels"}},
+			},
+		},
+		{
+			name: "code, shortcodes, links and identifiers are skipped",
+			rawContent: "+++\ntitle = 'Foo'\n+++\n\n## Code\n\n```go\nfmt.Pritnln(\"helo\")\n```\n\n" +
+				"Call `fmt.Pritnln` or [read teh docs](https://pkg.go.dev/fmt#Pritnln) {{< youtube dQw4w9WgXcQ >}}\n" +
+				"<!-- wrnog -->\nSee https://exmaple.com, os.ReadFile, JSON and JavaScript.\n",
+			want: []SpellingIssue{
+				{Word: "teh", Position: Position{Line: 11, Column: 29}, Suggestions: []string{"the", "Eth", "tea"}},
+			},
+		},
+		{
+			name:       "spell ignore and hyphens",
+			rawContent: "+++\ntitle = 'Foo'\nspellIgnore = [\"mdcheck\"]\n+++\n\nThe mdcheck command is well-knwon.\n",
+			want: []SpellingIssue{
+				{Word: "knwon", Position: Position{Line: 6, Column: 29}, Suggestions: []string{"known", "unwon", "kaon"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got, err := checker.Check(tt.rawContent)
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{a: "known", b: "known", limit: 2, want: 0},
+		{a: "knwon", b: "known", limit: 2, want: 1},
+		{a: "chanels", b: "channels", limit: 2, want: 1},
+		{a: "concurency", b: "congruency", limit: 2, want: 2},
+		{a: "concurency", b: "currency", limit: 2, want: 3},
+		{a: "teh", b: "technology", limit: 2, want: 3},
+		{a: "zürich", b: "zurich", limit: 1, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"-"+tt.b, func(t *testing.T) {
+			// execute
+			got := editDistance([]rune(tt.a), []rune(tt.b), tt.limit)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const TagVocabularyFileName = "tags.txt"
+
+// functionalTags change how pages are validated, therefore they are always allowed
+var functionalTags = []string{tagUsefulWithoutVideo, tagSlugForced, tagNoExercise, tagProjects}
+
+const maxTagSuggestions = 3
+
+// TagVocabulary is the list of allowed tags and their aliases
+type TagVocabulary struct {
+	tags    map[string]struct{}
+	aliases map[string]string
+}
+
+// ParseTagVocabulary parses a tag vocabulary file. Each line contains an allowed tag, optionally followed by a colon
+// and a comma separated list of its aliases. Empty lines and lines starting with # are ignored.
+//
+//	goroutines: goroutine, go-routines
+func ParseTagVocabulary(raw string) (TagVocabulary, error) {
+	vocabulary := TagVocabulary{
+		tags:    make(map[string]struct{}),
+		aliases: make(map[string]string),
+	}
+
+	for _, tag := range functionalTags {
+		vocabulary.tags[tag] = struct{}{}
+	}
+
+	for i, row := range strings.Split(strings.Replace(raw, "\r\n", EOL, -1), EOL) {
+		row = strings.TrimSpace(row)
+		if row == "" || strings.HasPrefix(row, "#") {
+			continue
+		}
+
+		tag, aliases, _ := strings.Cut(row, ":")
+
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return TagVocabulary{}, fmt.Errorf("missing tag on line %d", i+1)
+		}
+
+		vocabulary.tags[tag] = struct{}{}
+
+		for _, alias := range strings.Split(aliases, ",") {
+			alias = strings.TrimSpace(alias)
+			if alias == "" {
+				continue
+			}
+
+			if existing, ok := vocabulary.aliases[alias]; ok && existing != tag {
+				return TagVocabulary{}, fmt.Errorf("alias is used for multiple tags on line %d: %s", i+1, alias)
+			}
+
+			vocabulary.aliases[alias] = tag
+		}
+	}
+
+	for alias := range vocabulary.aliases {
+		if _, ok := vocabulary.tags[alias]; ok {
+			return TagVocabulary{}, fmt.Errorf("alias is also an allowed tag: %s", alias)
+		}
+	}
+
+	return vocabulary, nil
+}
+
+// Tags returns the allowed tags in alphabetical order
+func (tv TagVocabulary) Tags() []string {
+	tags := make([]string, 0, len(tv.tags))
+	for tag := range tv.tags {
+		tags = append(tags, tag)
+	}
+
+	sort.Strings(tags)
+
+	return tags
+}
+
+// IsEmpty returns true if no vocabulary was loaded
+func (tv TagVocabulary) IsEmpty() bool {
+	return tv.tags == nil
+}
+
+func (tv TagVocabulary) IsAllowed(tag string) bool {
+	_, ok := tv.tags[tag]
+
+	return ok
+}
+
+// GetIssue returns the problem with the given tag, an empty string is returned for allowed tags
+func (tv TagVocabulary) GetIssue(tag string) string {
+	if tv.IsAllowed(tag) {
+		return ""
+	}
+
+	if replacement, ok := tv.aliases[tag]; ok {
+		return fmt.Sprintf("tag is an alias of `%s`: %s", replacement, tag)
+	}
+
+	suggestions := tv.Suggest(tag)
+	if len(suggestions) == 0 {
+		return "unknown tag: " + tag
+	}
+
+	return fmt.Sprintf("unknown tag: %s (did you mean: %s?)", tag, strings.Join(suggestions, ", "))
+}
+
+// Suggest returns the allowed tags closest to the given one by edit distance
+func (tv TagVocabulary) Suggest(tag string) []string {
+	maxDistance := max(2, len(tag)/3)
+
+	type candidate struct {
+		tag      string
+		distance int
+	}
+
+	var candidates []candidate
+
+	for allowed := range tv.tags {
+		if distance := levenshtein(tag, allowed); distance <= maxDistance {
+			candidates = append(candidates, candidate{tag: allowed, distance: distance})
+		}
+	}
+
+	for alias, allowed := range tv.aliases {
+		if distance := levenshtein(tag, alias); distance <= maxDistance {
+			candidates = append(candidates, candidate{tag: allowed, distance: distance})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance == candidates[j].distance {
+			return candidates[i].tag < candidates[j].tag
+		}
+
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var suggestions []string
+
+	found := make(map[string]struct{}, len(candidates))
+	for _, c := range candidates {
+		if _, ok := found[c.tag]; ok {
+			continue
+		}
+
+		found[c.tag] = struct{}{}
+		suggestions = append(suggestions, c.tag)
+
+		if len(suggestions) == maxTagSuggestions {
+			break
+		}
+	}
+
+	return suggestions
+}
+
+// levenshtein returns the minimum number of single character edits needed to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// CheckTags adds an issue to every page using a tag which is not part of the vocabulary
+func (c Courses) CheckTags(vocabulary TagVocabulary) {
+	for _, course := range c {
+		for _, chapter := range course.Chapters {
+			for i, page := range chapter.Pages {
+				for _, tag := range page.Content.Tags {
+					if issue := vocabulary.GetIssue(tag); issue != "" {
+						chapter.Pages[i].Issues = append(chapter.Pages[i].Issues, issue)
+					}
+				}
+			}
+		}
+	}
+}
+
+type TagStat struct {
+	Tag     string
+	Pages   int
+	Courses []string
+}
+
+type TagStats []TagStat
+
+// TagStats counts the pages using each tag, ordered by the number of pages
+func (c Courses) TagStats() TagStats {
+	stats := make(map[string]*TagStat)
+
+	for _, course := range c {
+		for _, chapter := range course.Chapters {
+			for _, page := range chapter.Pages {
+				for _, tag := range page.Content.Tags {
+					stat, ok := stats[tag]
+					if !ok {
+						stat = &TagStat{Tag: tag}
+						stats[tag] = stat
+					}
+
+					stat.Pages++
+
+					if len(stat.Courses) == 0 || stat.Courses[len(stat.Courses)-1] != course.Title {
+						stat.Courses = append(stat.Courses, course.Title)
+					}
+				}
+			}
+		}
+	}
+
+	result := make(TagStats, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Pages == result[j].Pages {
+			return result[i].Tag < result[j].Tag
+		}
+
+		return result[i].Pages > result[j].Pages
+	})
+
+	return result
+}
+
+// String returns the tag stats as a table, unknown tags are highlighted if a vocabulary is provided
+func (ts TagStats) String(vocabulary TagVocabulary) string {
+	width := len("Tag")
+	for _, stat := range ts {
+		width = max(width, len(stat.Tag))
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s | %s | %s\n", column("Tag", width, cliBold), column("Pages", 5, cliBold), column("Courses", 7, cliBold)))
+	sb.WriteString(strings.Repeat("-", width+1) + "+" + strings.Repeat("-", 7) + "+" + strings.Repeat("-", 9) + EOL)
+
+	for _, stat := range ts {
+		color := cliReset
+		if !vocabulary.IsEmpty() && !vocabulary.IsAllowed(stat.Tag) {
+			color = cliRed
+		}
+
+		sb.WriteString(fmt.Sprintf("%s | %s | %s\n", column(stat.Tag, width, color), column(stat.Pages, 5, cliReset), strings.Join(stat.Courses, ", ")))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,168 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTagVocabulary = `# concurrency
+goroutines: goroutine, go-routines
+channels: chan
+
+linux
+vim
+`
+
+func TestParseTagVocabulary(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			raw:  testTagVocabulary,
+		},
+		{
+			name:    "missing tag",
+			raw:     ": foo",
+			wantErr: true,
+		},
+		{
+			name:    "alias used twice",
+			raw:     "foo: baz\nbar: baz",
+			wantErr: true,
+		},
+		{
+			name:    "alias is a tag",
+			raw:     "foo: bar\nbar",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got, err := ParseTagVocabulary(tt.raw)
+
+			// verify
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.False(t, got.IsEmpty())
+		})
+	}
+}
+
+func TestTagVocabulary_GetIssue(t *testing.T) {
+	vocabulary, err := ParseTagVocabulary(testTagVocabulary)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{
+			name: "allowed",
+			tag:  "goroutines",
+			want: "",
+		},
+		{
+			name: "functional",
+			tag:  tagNoExercise,
+			want: "",
+		},
+		{
+			name: "alias",
+			tag:  "goroutine",
+			want: "tag is an alias of `goroutines`: goroutine",
+		},
+		{
+			name: "typo",
+			tag:  "chanels",
+			want: "unknown tag: chanels (did you mean: channels?)",
+		},
+		{
+			name: "typo of an alias",
+			tag:  "go-routine",
+			want: "unknown tag: go-routine (did you mean: goroutines?)",
+		},
+		{
+			name: "unknown",
+			tag:  "kubernetes",
+			want: "unknown tag: kubernetes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// execute
+			got := vocabulary.GetIssue(tt.tag)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_levenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "go", b: "", want: 2},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "goroutine", b: "goroutines", want: 1},
+		{a: "żółw", b: "zółw", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"-"+tt.b, func(t *testing.T) {
+			// execute
+			got := levenshtein(tt.a, tt.b)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCourses_TagStats(t *testing.T) {
+	newPage := func(tags ...string) Page {
+		return Page{Content: Content{Tags: tags, Body: DefaultBody{}}}
+	}
+
+	courses := Courses{
+		{Title: "a1", Chapters: Chapters{{Pages: Pages{newPage("go", "vim"), newPage("go")}}}},
+		{Title: "a2", Chapters: Chapters{{Pages: Pages{newPage("vim")}}, {Pages: Pages{newPage("go")}}}},
+	}
+
+	// execute
+	got := courses.TagStats()
+
+	// verify
+	assert.Equal(t, TagStats{
+		{Tag: "go", Pages: 3, Courses: []string{"a1", "a2"}},
+		{Tag: "vim", Pages: 2, Courses: []string{"a1", "a2"}},
+	}, got)
+}
+
+func TestCourses_CheckTags(t *testing.T) {
+	vocabulary, err := ParseTagVocabulary(testTagVocabulary)
+	require.NoError(t, err)
+
+	courses := Courses{
+		{Title: "a1", Chapters: Chapters{{Pages: Pages{{Content: Content{Tags: []string{"vim", "vi"}, Body: DefaultBody{}}}}}}},
+	}
+
+	// execute
+	courses.CheckTags(vocabulary)
+
+	// verify
+	assert.Equal(t, []string{"unknown tag: vi (did you mean: vim?)"}, courses[0].Chapters[0].Pages[0].Issues)
+}
@@ -0,0 +1,235 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const indexFileName = "_index.md"
+
+var (
+	regexLanguageSuffix     = regexp.MustCompile(`^(.+)\.([a-z]{2}(?:-[a-z]{2})?)\.md$`)
+	regexLanguageContentDir = regexp.MustCompile(`^content\.([a-z]{2}(?:-[a-z]{2})?)$`)
+)
+
+// SplitLanguage splits the file name of a Hugo translation (e.g. `10-intro.pl.md`) into the file name of the source
+// page (`10-intro.md`) and the language code (`pl`). The language code is empty for pages in the default language.
+// Slugs can not contain dots, therefore a two-letter code before the extension is always a language code.
+func SplitLanguage(fileName string) (string, string) {
+	matches := regexLanguageSuffix.FindStringSubmatch(fileName)
+	if matches == nil {
+		return fileName, ""
+	}
+
+	return matches[1] + ".md", matches[2]
+}
+
+// Language returns the language code of a page found either in its file name (`content/a1/go/10-intro.pl.md`) or in
+// the name of its per-language content directory (`content.pl/a1/go/10-intro.md`)
+func Language(filePath string) string {
+	if _, language := SplitLanguage(filepath.Base(filePath)); language != "" {
+		return language
+	}
+
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(filePath)), "/") {
+		if matches := regexLanguageContentDir.FindStringSubmatch(dir); matches != nil {
+			return matches[1]
+		}
+	}
+
+	return ""
+}
+
+// sourceFileName returns the file name of the page in the default language
+func (p Page) sourceFileName() string {
+	fileName, _ := SplitLanguage(filepath.Base(p.FilePath))
+
+	return fileName
+}
+
+func newPage(filePath, title string, content Content) Page {
+	return Page{FilePath: filePath, Title: title, Content: content, Language: Language(filePath)}
+}
+
+// groupTranslations moves the translated pages of the chapter to the translations of their source pages, translations
+// without a source page are kept as they are
+func (c *Chapter) groupTranslations() {
+	sources := make(map[string]int, len(c.Pages))
+	pages := make(Pages, 0, len(c.Pages))
+
+	for _, page := range c.Pages {
+		if page.Language != "" {
+			continue
+		}
+
+		sources[page.sourceFileName()] = len(pages)
+		pages = append(pages, page)
+	}
+
+	for _, page := range c.Pages {
+		if page.Language == "" {
+			continue
+		}
+
+		i, ok := sources[page.sourceFileName()]
+		if !ok {
+			page.Issues = append(page.Issues, "translation without a source page: "+page.sourceFileName())
+			pages = append(pages, page)
+
+			continue
+		}
+
+		pages[i].Translations = append(pages[i].Translations, page)
+	}
+
+	c.Pages = pages
+}
+
+// Languages returns the languages of the translations found, sorted alphabetically
+func (c Courses) Languages() []string {
+	found := make(map[string]struct{})
+
+	collect := func(pages Pages) {
+		for _, page := range pages {
+			if page.Language != "" {
+				found[page.Language] = struct{}{}
+			}
+
+			for _, translation := range page.Translations {
+				found[translation.Language] = struct{}{}
+			}
+		}
+	}
+
+	for _, course := range c {
+		collect(course.Translations)
+
+		for _, chapter := range course.Chapters {
+			collect(chapter.Pages)
+		}
+	}
+
+	languages := make([]string, 0, len(found))
+	for language := range found {
+		languages = append(languages, language)
+	}
+
+	sort.Strings(languages)
+
+	return languages
+}
+
+// CheckTranslations adds an issue to every page without a translation to one of the languages given and to every
+// translation which does not match its source page. Translations committed before their source pages are reported if
+// the commit times of the files are known.
+func (c Courses) CheckTranslations(languages []string, commitTimes map[string]time.Time) {
+	for i, course := range c {
+		if course.Index != nil {
+			checkTranslations(course.Index, c[i].Translations, languages, commitTimes)
+		}
+
+		for _, chapter := range course.Chapters {
+			for j := range chapter.Pages {
+				// translations without a source page are reported while grouping
+				if chapter.Pages[j].Language != "" {
+					continue
+				}
+
+				checkTranslations(&chapter.Pages[j], chapter.Pages[j].Translations, languages, commitTimes)
+			}
+		}
+	}
+}
+
+func checkTranslations(source *Page, translations Pages, languages []string, commitTimes map[string]time.Time) {
+	translated := make(map[string]string, len(translations))
+
+	for i, translation := range translations {
+		if filePath, exists := translated[translation.Language]; exists {
+			translations[i].Issues = append(translations[i].Issues, fmt.Sprintf("duplicate translation: %s, also in %s", translation.Language, filePath))
+
+			continue
+		}
+
+		translated[translation.Language] = translation.FilePath
+
+		if translation.Content.Weight != source.Content.Weight {
+			translations[i].Issues = append(translations[i].Issues, fmt.Sprintf("translation weight differs from the source: %s, want: %s", translation.Content.Weight, source.Content.Weight))
+		}
+
+		if translation.Content.Slug != source.Content.Slug {
+			translations[i].Issues = append(translations[i].Issues, fmt.Sprintf("translation slug differs from the source: %s, want: %s", translation.Content.Slug, source.Content.Slug))
+		}
+
+		sourceTime, ok := commitTimes[source.FilePath]
+		if !ok {
+			continue
+		}
+
+		translationTime, ok := commitTimes[translation.FilePath]
+		if ok && translationTime.Before(sourceTime) {
+			translations[i].Issues = append(translations[i].Issues, fmt.Sprintf("translation is older than its source: %s, source changed: %s", translationTime.Format(time.DateOnly), sourceTime.Format(time.DateOnly)))
+		}
+	}
+
+	for _, language := range languages {
+		if _, ok := translated[language]; !ok {
+			source.Issues = append(source.Issues, "translation is missing: "+language)
+		}
+	}
+}
+
+// CommitTimes returns the time of the last commit of every file tracked by git in root, keyed by the file paths
+// joined with root
+func CommitTimes(root string) (map[string]time.Time, error) {
+	cmd := exec.Command("git", "-C", root, "-c", "core.quotepath=off", "log", "--format=%x00%ct", "--name-only", "--relative")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read git log of %s, err: %w", root, err)
+	}
+
+	return parseCommitTimes(output, root)
+}
+
+// parseCommitTimes parses the output of `git log --format=%x00%ct --name-only`, the log lists the newest commits first
+func parseCommitTimes(output []byte, root string) (map[string]time.Time, error) {
+	commitTimes := make(map[string]time.Time)
+
+	var current time.Time
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "\x00") {
+			seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "\x00"), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid commit time: %s", line[1:])
+			}
+
+			current = time.Unix(seconds, 0).UTC()
+
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		filePath := filepath.Join(root, line)
+		if _, exists := commitTimes[filePath]; !exists {
+			commitTimes[filePath] = current
+		}
+	}
+
+	return commitTimes, scanner.Err()
+}
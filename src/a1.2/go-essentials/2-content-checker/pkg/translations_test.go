@@ -0,0 +1,183 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitLanguage(t *testing.T) {
+	tests := []struct {
+		fileName     string
+		wantFileName string
+		wantLanguage string
+	}{
+		{fileName: "10-intro.md", wantFileName: "10-intro.md", wantLanguage: ""},
+		{fileName: "10-intro.pl.md", wantFileName: "10-intro.md", wantLanguage: "pl"},
+		{fileName: "_index.de.md", wantFileName: "_index.md", wantLanguage: "de"},
+		{fileName: "10-intro.pt-br.md", wantFileName: "10-intro.md", wantLanguage: "pt-br"},
+		{fileName: "10-intro.toml.md", wantFileName: "10-intro.toml.md", wantLanguage: ""},
+		{fileName: "pl.md", wantFileName: "pl.md", wantLanguage: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			// execute
+			gotFileName, gotLanguage := SplitLanguage(tt.fileName)
+
+			// verify
+			assert.Equal(t, tt.wantFileName, gotFileName)
+			assert.Equal(t, tt.wantLanguage, gotLanguage)
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		filePath string
+		want     string
+	}{
+		{filePath: "site/content/a1/go/10-intro.md", want: ""},
+		{filePath: "site/content/a1/go/10-intro.pl.md", want: "pl"},
+		{filePath: "site/content.de/a1/go/10-intro.md", want: "de"},
+		{filePath: "site/content.de/a1/_index.md", want: "de"},
+		{filePath: "site/content.old/a1/go/10-intro.md", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filePath, func(t *testing.T) {
+			// execute
+			got := Language(tt.filePath)
+
+			// verify
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCourses_AddIndex_Translations(t *testing.T) {
+	content := Content{Title: "Foo", Body: DefaultBody{}}
+
+	// execute
+	got := Courses{}.AddIndex("content/foo/_index.pl.md", "foo", content).AddIndex("content/foo/_index.md", "foo", content)
+
+	// verify
+	assert.Equal(t, Courses{
+		{
+			Title:        "foo",
+			Index:        &Page{FilePath: "content/foo/_index.md", Title: "_index.md", Content: content},
+			Translations: Pages{{FilePath: "content/foo/_index.pl.md", Title: "_index.pl.md", Content: content, Language: "pl"}},
+		},
+	}, got)
+}
+
+func TestChapter_Prepare_Translations(t *testing.T) {
+	content := func(weight string) Content {
+		return Content{Weight: weight, Body: DefaultBody{}}
+	}
+
+	chapter := &Chapter{Title: "go"}
+	chapter.Pages = chapter.Pages.
+		Add("content/a1/go/20-bar.pl.md", "20-bar.pl.md", content("20")).
+		Add("content/a1/go/20-bar.md", "20-bar.md", content("20")).
+		Add("content.de/a1/go/20-bar.md", "20-bar.md", content("20")).
+		Add("content/a1/go/10-foo.md", "10-foo.md", content("10")).
+		Add("content/a1/go/30-baz.pl.md", "30-baz.pl.md", content("30"))
+
+	// execute
+	chapter.Prepare()
+
+	// verify
+	require.Len(t, chapter.Pages, 3)
+	assert.Equal(t, "content/a1/go/10-foo.md", chapter.Pages[0].FilePath)
+	assert.Empty(t, chapter.Pages[0].Translations)
+	assert.Equal(t, "content/a1/go/20-bar.md", chapter.Pages[1].FilePath)
+	require.Len(t, chapter.Pages[1].Translations, 2)
+	assert.Equal(t, "pl", chapter.Pages[1].Translations[0].Language)
+	assert.Equal(t, "de", chapter.Pages[1].Translations[1].Language)
+	assert.Equal(t, "content/a1/go/30-baz.pl.md", chapter.Pages[2].FilePath)
+	assert.Equal(t, []string{"translation without a source page: 30-baz.md"}, chapter.Pages[2].Issues)
+}
+
+func TestCourses_CheckTranslations(t *testing.T) {
+	source := Content{Weight: "10", Slug: "foo", Body: DefaultBody{}}
+	index := Content{Title: "A1", Body: DefaultBody{}}
+
+	courses := Courses{
+		{
+			Title:        "a1",
+			Index:        &Page{FilePath: "content/a1/_index.md", Content: index},
+			Translations: Pages{{FilePath: "content/a1/_index.pl.md", Content: index, Language: "pl"}},
+			Chapters: Chapters{{Title: "go", Pages: Pages{{
+				FilePath: "content/a1/go/10-foo.md",
+				Content:  source,
+				Translations: Pages{
+					{FilePath: "content/a1/go/10-foo.pl.md", Content: Content{Weight: "20", Slug: "bar"}, Language: "pl"},
+					{FilePath: "content.pl/a1/go/10-foo.md", Content: source, Language: "pl"},
+				},
+			}}}},
+		},
+	}
+
+	commitTimes := map[string]time.Time{
+		"content/a1/_index.md":    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		"content/a1/_index.pl.md": time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// execute
+	courses.CheckTranslations([]string{"pl", "de"}, commitTimes)
+
+	// verify
+	assert.Equal(t, []string{"translation is missing: de"}, courses[0].Index.Issues)
+	assert.Equal(t, []string{"translation is older than its source: 2024-04-01, source changed: 2024-05-01"}, courses[0].Translations[0].Issues)
+
+	page := courses[0].Chapters[0].Pages[0]
+	assert.Equal(t, []string{"translation is missing: de"}, page.Issues)
+	assert.Equal(t, []string{
+		"translation weight differs from the source: 20, want: 10",
+		"translation slug differs from the source: bar, want: foo",
+	}, page.Translations[0].Issues)
+	assert.Equal(t, []string{"duplicate translation: pl, also in content/a1/go/10-foo.pl.md"}, page.Translations[1].Issues)
+}
+
+func TestCourses_Languages(t *testing.T) {
+	courses := Courses{
+		{
+			Translations: Pages{{FilePath: "content/a1/_index.pl.md", Language: "pl"}},
+			Chapters: Chapters{{Pages: Pages{
+				{FilePath: "content/a1/go/10-foo.md", Translations: Pages{{FilePath: "content.de/a1/go/10-foo.md", Language: "de"}}},
+				{FilePath: "content/a1/go/20-bar.fr.md", Language: "fr"},
+			}}},
+		},
+	}
+
+	// execute
+	got := courses.Languages()
+
+	// verify
+	assert.Equal(t, []string{"de", "fr", "pl"}, got)
+}
+
+func TestContent_GetIssues_Translation(t *testing.T) {
+	content := Content{Title: "Wprowadzenie", Weight: "10", Slug: "intro", Audience: All, Body: DefaultBody{}}
+
+	// execute
+	got := content.GetIssues("content/a1/go/10-intro.pl.md")
+
+	// verify
+	assert.Empty(t, got)
+}
+
+func Test_parseCommitTimes(t *testing.T) {
+	output := "\x001714521600\n\ncontent/a1/go/10-foo.md\n\n\x001711929600\n\ncontent/a1/go/10-foo.md\ncontent/a1/go/10-foo.pl.md\n"
+
+	// execute
+	got, err := parseCommitTimes([]byte(output), "site")
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{
+		"site/content/a1/go/10-foo.md":    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		"site/content/a1/go/10-foo.pl.md": time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}, got)
+}